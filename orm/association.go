@@ -0,0 +1,183 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"slices"
+)
+
+// ---------------------------------------------------------------------------
+// Many-to-many associations --------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// ManyToMany — many-to-many relation through a join table (J) which rows
+// reference an owner row and a target (T) row by K key. Targets are held by
+// the repeated field of the owner message.
+type ManyToMany[
+	K any,
+	JF fieldAlias, JS targeter[JF],
+	TF fieldAlias, TS targeter[TF], TT proto.Message,
+] struct {
+	field     protoreflect.Name
+	ownerPK   string
+	join      *table[JF, JS]
+	target    *table[TF, TS]
+	ownerKey  JF
+	targetKey JF
+	targetPK  TF
+	upcast    TypeCaster[TS, TT]
+	downcast  TypeCaster[TT, TS]
+}
+
+func newManyToMany[
+	K any,
+	JF fieldAlias, JS targeter[JF],
+	TF fieldAlias, TS targeter[TF], TT proto.Message,
+](
+	field protoreflect.Name,
+	ownerPK string,
+	join *table[JF, JS],
+	target *table[TF, TS],
+	ownerKey JF,
+	targetKey JF,
+	targetPK TF,
+	upcast TypeCaster[TS, TT],
+	downcast TypeCaster[TT, TS],
+) *ManyToMany[K, JF, JS, TF, TS, TT] {
+	return &ManyToMany[K, JF, JS, TF, TS, TT]{
+		field: field, ownerPK: ownerPK,
+		join: join, target: target, ownerKey: ownerKey, targetKey: targetKey, targetPK: targetPK,
+		upcast: upcast, downcast: downcast,
+	}
+}
+
+// WithCasters sets casters of the targets. Generated relations have them
+// unless the target table needs user casters.
+func (m *ManyToMany[K, JF, JS, TF, TS, TT]) WithCasters(
+	upcast TypeCaster[TS, TT],
+	downcast TypeCaster[TT, TS],
+) *ManyToMany[K, JF, JS, TF, TS, TT] {
+	ret := *m
+	ret.upcast, ret.downcast = upcast, downcast
+	return &ret
+}
+
+// Of returns associations of the owner row
+func (m *ManyToMany[K, JF, JS, TF, TS, TT]) Of(dbGetter DbGetter, owner any) *Association[K, JF, JS, TF, TS, TT] {
+	return &Association[K, JF, JS, TF, TS, TT]{relation: m, dbGetter: dbGetter, owner: owner}
+}
+
+// Association — targets of one owner row. Mutations run in one transaction
+// and are idempotent: existing join rows are kept with ON CONFLICT DO NOTHING
+// on the (owner, target) unique constraint.
+type Association[
+	K any,
+	JF fieldAlias, JS targeter[JF],
+	TF fieldAlias, TS targeter[TF], TT proto.Message,
+] struct {
+	relation *ManyToMany[K, JF, JS, TF, TS, TT]
+	dbGetter DbGetter
+	owner    any
+}
+
+func (a *Association[K, JF, JS, TF, TS, TT]) ownerClause() Clause[JF] {
+	return &FieldClause[JF]{Field: a.relation.ownerKey, Operator: "=", Right: &ParamExprClause[JF]{Value: a.owner}}
+}
+
+func (a *Association[K, JF, JS, TF, TS, TT]) insert(targets []K, extra []ValueSetter[JF]) *InsertQuery[JF] {
+	rows := make([][]ValueSetter[JF], len(targets))
+	for i, target := range targets {
+		rows[i] = slices.Concat([]ValueSetter[JF]{
+			NewValueSetter(a.relation.ownerKey, a.owner),
+			NewValueSetter(a.relation.targetKey, target),
+		}, extra)
+	}
+	return a.relation.join.Insert().Rows(rows...).OnConflict(a.relation.ownerKey, a.relation.targetKey).DoNothing()
+}
+
+// Add links targets to the owner, already linked ones are skipped
+func (a *Association[K, JF, JS, TF, TS, TT]) Add(ctx context.Context, targets ...K) error {
+	return a.AddWith(ctx, nil, targets...)
+}
+
+// AddWith links targets with extra columns of the join table, e.g.
+// []ValueSetter[UserTagsField]{NewValueSetter[UserTagsField](UserTags.Role, "owner")}
+func (a *Association[K, JF, JS, TF, TS, TT]) AddWith(ctx context.Context, extra []ValueSetter[JF], targets ...K) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	return runInTx(ctx, a.dbGetter, func(ctx context.Context, db DB) error {
+		_, err := a.relation.join.Execute(ctx, db, a.insert(targets, extra))
+		return err
+	})
+}
+
+// Remove unlinks targets from the owner, missing links are ignored
+func (a *Association[K, JF, JS, TF, TS, TT]) Remove(ctx context.Context, targets ...K) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	return runInTx(ctx, a.dbGetter, func(ctx context.Context, db DB) error {
+		_, err := a.relation.join.Execute(ctx, db, a.relation.join.Delete().Where(
+			a.ownerClause(),
+			&FieldClause[JF]{Field: a.relation.targetKey, Operator: "= ANY", Right: &SliceExprClause[JF]{Values: targets}},
+		))
+		return err
+	})
+}
+
+// Replace makes targets the only links of the owner, extra columns of kept
+// links are not changed
+func (a *Association[K, JF, JS, TF, TS, TT]) Replace(ctx context.Context, targets ...K) error {
+	return a.ReplaceWith(ctx, nil, targets...)
+}
+
+// ReplaceWith — Replace with extra columns of added links
+func (a *Association[K, JF, JS, TF, TS, TT]) ReplaceWith(ctx context.Context, extra []ValueSetter[JF], targets ...K) error {
+	return runInTx(ctx, a.dbGetter, func(ctx context.Context, db DB) error {
+		clauses := []Clause[JF]{a.ownerClause()}
+		if len(targets) > 0 {
+			clauses = append(clauses, &FieldClause[JF]{
+				Field: a.relation.targetKey, Operator: "= ANY", Right: &SliceExprClause[JF]{Values: targets}, Negate: true,
+			})
+		}
+		if _, err := a.relation.join.Execute(ctx, db, a.relation.join.Delete().Where(clauses...)); err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+		_, err := a.relation.join.Execute(ctx, db, a.insert(targets, extra))
+		return err
+	})
+}
+
+// Query selects targets of the owner, it may be extended with clauses and
+// ordering before running
+func (a *Association[K, JF, JS, TF, TS, TT]) Query() *SelectQuery[TF] {
+	return a.relation.target.SelectAll().Where(&FieldClause[TF]{
+		Field:    a.relation.targetPK,
+		Operator: "IN",
+		Right: &SubQueryExprClause[TF]{
+			Query: a.relation.join.Select(a.relation.targetKey).Where(a.ownerClause()),
+		},
+	})
+}
+
+// List returns targets of the owner, see ManyToMany.WithCasters
+func (a *Association[K, JF, JS, TF, TS, TT]) List(ctx context.Context) ([]TT, error) {
+	if a.relation.upcast == nil {
+		return nil, fmt.Errorf("pgx-orm: list %s: casters of %s are not set, see WithCasters", a.relation.join.alias, a.relation.target.alias)
+	}
+	rows, err := a.relation.target.Query(ctx, a.dbGetter(ctx, SqlQuery), a.Query())
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]TT, len(rows))
+	for i, row := range rows {
+		ret[i] = a.relation.upcast(row)
+	}
+	return ret, nil
+}
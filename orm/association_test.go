@@ -0,0 +1,54 @@
+package orm
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"testing"
+)
+
+type testExecDB struct {
+	DB
+	sql  []string
+	args [][]any
+}
+
+func (d *testExecDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	d.sql = append(d.sql, sql)
+	d.args = append(d.args, args)
+	return pgconn.CommandTag{}, nil
+}
+
+func TestAssociation(t *testing.T) {
+	join := newTable[fieldAlias, testScanner]("user_tags", func() testScanner { return testScanner{} },
+		testField("users_id"), testField("tags_id"), testField("role"))
+	tags := newTable[fieldAlias, testScanner]("tags", func() testScanner { return testScanner{} }, testField("id"))
+	m2m := newManyToMany[int64, fieldAlias, testScanner, fieldAlias, testScanner, *structpb.Value](
+		"tags", "id", join, tags, testField("users_id"), testField("tags_id"), testField("id"), nil, nil,
+	)
+	db := &testExecDB{}
+	assoc := m2m.Of(NewDbGetter(db), int64(1))
+
+	err := assoc.ReplaceWith(context.Background(), []ValueSetter[fieldAlias]{NewValueSetter[fieldAlias](testField("role"), "owner")}, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DELETE FROM user_tags WHERE user_tags.users_id = $1 AND NOT (user_tags.tags_id = ANY ($2));",
+		"INSERT INTO user_tags (users_id, tags_id, role) VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT (users_id, tags_id) DO NOTHING;",
+	}
+	if len(db.sql) != len(want) {
+		t.Fatalf("replace queries fail: %v", db.sql)
+	}
+	for i := range want {
+		if db.sql[i] != want[i] {
+			t.Fatalf("replace query %d fail:\nwant: %s\ngot : %s", i, want[i], db.sql[i])
+		}
+	}
+
+	sql, args := assoc.Query().Build()
+	wantList := "SELECT tags.id FROM tags AS tags WHERE tags.id IN (SELECT user_tags.tags_id FROM user_tags AS user_tags WHERE user_tags.users_id = $1);"
+	if sql != wantList || len(args) != 1 {
+		t.Fatalf("list query fail:\nwant: %s\ngot : %s %v", wantList, sql, args)
+	}
+}
@@ -0,0 +1,176 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+)
+
+// ---------------------------------------------------------------------------
+// Batch ----------------------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+var (
+	ErrBatchNotSent      = errors.New("batch is not sent")
+	ErrBatchNotSupported = errors.New("db does not support batches")
+)
+
+// BatchSender is implemented by *pgxpool.Pool, *pgx.Conn and pgx.Tx
+type BatchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// readQuery marks selects, see Batch.SendWith
+type readQuery interface {
+	readOnly()
+}
+
+type batchItem struct {
+	query    ormQuery
+	mutation bool
+	handle   func(results pgx.BatchResults) error
+}
+
+// Batch queues queries of any tables, Send executes them in one round trip
+// and fills BatchResult of every queued query
+type Batch struct {
+	items []batchItem
+}
+
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+func (b *Batch) queue(query ormQuery, handle func(results pgx.BatchResults) error) {
+	_, read := query.(readQuery)
+	b.items = append(b.items, batchItem{query: query, mutation: !read, handle: handle})
+}
+
+// Send executes queued queries, the returned error joins errors of all items
+func (b *Batch) Send(ctx context.Context, db DB) error {
+	db, _ = unobserved(db)
+	sender, ok := db.(BatchSender)
+	if !ok {
+		return ErrBatchNotSupported
+	}
+	batch := &pgx.Batch{}
+	for _, item := range b.items {
+		sql, args := item.query.Build()
+		batch.Queue(sql, args...)
+	}
+	results := sender.SendBatch(ctx, batch)
+	errs := make([]error, 0, len(b.items)+1)
+	for _, item := range b.items {
+		errs = append(errs, item.handle(results))
+	}
+	errs = append(errs, results.Close())
+	return errors.Join(errs...)
+}
+
+// SendWith sends the batch to db of getter, SqlQuery is used when every
+// queued query is a select
+func (b *Batch) SendWith(ctx context.Context, getter DbGetter) error {
+	operation := SqlQuery
+	for _, item := range b.items {
+		if item.mutation {
+			operation = SqlMutation
+		}
+	}
+	return b.Send(ctx, getter(ctx, operation))
+}
+
+// BatchResult of a queued query, available after Batch.Send
+type BatchResult[R any] struct {
+	get func() (R, error)
+}
+
+func (r *BatchResult[R]) Result() (R, error) {
+	return r.get()
+}
+
+type batchValue[R any] struct {
+	value R
+	err   error
+}
+
+// add merges result of one statement of a chunked query
+func (v *batchValue[R]) add(err error) error {
+	if errors.Is(v.err, ErrBatchNotSent) {
+		v.err = nil
+	}
+	v.err = errors.Join(v.err, err)
+	return err
+}
+
+func newBatchResult[R any]() (*batchValue[R], *BatchResult[R]) {
+	v := &batchValue[R]{err: ErrBatchNotSent}
+	return v, &BatchResult[R]{get: func() (R, error) { return v.value, v.err }}
+}
+
+func failedBatchResult[R any](err error) *BatchResult[R] {
+	return &BatchResult[R]{get: func() (ret R, _ error) { return ret, err }}
+}
+
+// mapBatchResult converts result lazily on Result call
+func mapBatchResult[A, B any](src *BatchResult[A], fn func(A) B) *BatchResult[B] {
+	return &BatchResult[B]{get: func() (ret B, err error) {
+		value, err := src.Result()
+		if err != nil {
+			return ret, err
+		}
+		return fn(value), nil
+	}}
+}
+
+// QueueQuery queues query scanning all returned rows, queries over the
+// parameters limit are queued as several statements
+func (t *table[F, T]) QueueQuery(b *Batch, query ormQuery) *BatchResult[[]T] {
+	if err := queryErr(query); err != nil {
+		return failedBatchResult[[]T](err)
+	}
+	value, result := newBatchResult[[]T]()
+	for _, part := range splitQuery(query) {
+		b.queue(part, func(results pgx.BatchResults) error {
+			rows, err := results.Query()
+			if err == nil {
+				value.value, err = t.scanRows(rows, part.scanAbleFields(), value.value)
+			}
+			return value.add(t.wrapError(err))
+		})
+	}
+	return result
+}
+
+func (t *table[F, T]) QueueQueryRow(b *Batch, query ormQuery) *BatchResult[T] {
+	if err := queryErr(query); err != nil {
+		return failedBatchResult[T](err)
+	}
+	value, result := newBatchResult[T]()
+	b.queue(query, func(results pgx.BatchResults) error {
+		trg, err := t.scanRow(results.QueryRow(), query.scanAbleFields())
+		value.value, value.err = trg, t.wrapError(err)
+		return value.err
+	})
+	return result
+}
+
+// QueueExec queues query returning affected rows count, statements of a batch
+// run in one implicit transaction
+func (t *table[F, T]) QueueExec(b *Batch, query ormQuery) *BatchResult[int64] {
+	if err := queryErr(query); err != nil {
+		return failedBatchResult[int64](err)
+	}
+	value, result := newBatchResult[int64]()
+	for _, part := range splitQuery(query) {
+		b.queue(part, func(results pgx.BatchResults) error {
+			tag, err := results.Exec()
+			value.value += tag.RowsAffected()
+			return value.add(t.wrapError(err))
+		})
+	}
+	return result
+}
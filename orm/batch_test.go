@@ -0,0 +1,63 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"testing"
+)
+
+type testBatchResults struct {
+	pgx.BatchResults
+	affected []int64
+}
+
+func (r *testBatchResults) Exec() (pgconn.CommandTag, error) {
+	n := r.affected[0]
+	r.affected = r.affected[1:]
+	if n < 0 {
+		return pgconn.CommandTag{}, &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_pkey"}
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("DELETE %d", n)), nil
+}
+func (r *testBatchResults) Close() error { return nil }
+
+type testBatchDB struct {
+	DB
+	sqls    []string
+	results *testBatchResults
+}
+
+func (d *testBatchDB) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	for _, q := range b.QueuedQueries {
+		d.sqls = append(d.sqls, q.SQL)
+	}
+	return d.results
+}
+
+func TestBatch(t *testing.T) {
+	tbl := newTestTable("id", "name")
+	b := NewBatch()
+	first := tbl.QueueExec(b, tbl.Delete().Where(tbl.Raw("id = ?", 1)))
+	second := tbl.QueueExec(b, tbl.Delete().Where(tbl.Raw("id = ?", 2)))
+	if _, err := first.Result(); !errors.Is(err, ErrBatchNotSent) {
+		t.Fatalf("want ErrBatchNotSent, got %v", err)
+	}
+	if err := b.Send(context.Background(), struct{ DB }{}); !errors.Is(err, ErrBatchNotSupported) {
+		t.Fatalf("want ErrBatchNotSupported, got %v", err)
+	}
+	db := &testBatchDB{results: &testBatchResults{affected: []int64{2, -1}}}
+	err := b.Send(context.Background(), db)
+	if len(db.sqls) != 2 || b.Len() != 2 {
+		t.Fatalf("want 2 queued statements, got %v", db.sqls)
+	}
+	if affected, err := first.Result(); err != nil || affected != 2 {
+		t.Fatalf("first result: %d %v", affected, err)
+	}
+	var violation *ErrUniqueViolation
+	if _, err2 := second.Result(); !errors.As(err2, &violation) || !errors.As(err, &violation) {
+		t.Fatalf("want unique violation, got %v / %v", err2, err)
+	}
+}
@@ -44,6 +44,8 @@ type setterOperator[V any, F fieldAlias] interface {
 	SetExpr(string) *valueSetterImpl[F]
 	Set(V) *valueSetterImpl[F]
 	SetRaw(sql string, value ...any) *valueSetterImpl[F]
+	SetDefault() *valueSetterImpl[F]
+	SetExcluded() *valueSetterImpl[F]
 }
 type eqOperator[V any, F fieldAlias] interface {
 	Eq(V) Clause[F]
@@ -64,8 +66,25 @@ type anyQOperator[V any, F fieldAlias] interface {
 	ExistsOf(query ormQuery) Clause[F]
 	ExistsRaw(string, ...any) Clause[F]
 }
+type windowOperator[V any, F fieldAlias] interface {
+	Lag(offset int) *WindowExpr[F]
+	Lead(offset int) *WindowExpr[F]
+	FirstValue() *WindowExpr[F]
+	LastValue() *WindowExpr[F]
+	SumOver() *WindowExpr[F]
+	AvgOver() *WindowExpr[F]
+	MinOver() *WindowExpr[F]
+	MaxOver() *WindowExpr[F]
+	CountOver() *WindowExpr[F]
+}
+type orderOperator[V any, F fieldAlias] interface {
+	Asc() OrderTerm[F]
+	Desc() OrderTerm[F]
+}
 type CommonOperator[V any, F fieldAlias] interface {
 	Count() *countImpl[F]
+	orderOperator[V, F]
+	windowOperator[V, F]
 	setterOperator[V, F]
 	eqOperator[V, F]
 	logicalOperator[V, F]
@@ -99,6 +118,13 @@ func (f *column[V, F]) Count() *countImpl[F] {
 	}
 }
 
+func (f *column[V, F]) Asc() OrderTerm[F] {
+	return Asc(f.fieldAlias)
+}
+func (f *column[V, F]) Desc() OrderTerm[F] {
+	return Desc(f.fieldAlias)
+}
+
 func (f *column[V, F]) Set(val V) *valueSetterImpl[F] {
 	return &valueSetterImpl[F]{
 		field: f.fieldAlias,
@@ -111,6 +137,16 @@ func (f *column[V, F]) SetExpr(expr string) *valueSetterImpl[F] {
 		expr:  expr,
 	}
 }
+
+// SetDefault — column = DEFAULT in UPDATE, DEFAULT item in INSERT VALUES
+func (f *column[V, F]) SetDefault() *valueSetterImpl[F] {
+	return f.SetExpr("DEFAULT")
+}
+
+// SetExcluded — column = EXCLUDED.column in ON CONFLICT DO UPDATE
+func (f *column[V, F]) SetExcluded() *valueSetterImpl[F] {
+	return f.SetExpr("EXCLUDED." + f.fieldAlias.String())
+}
 func (f *column[V, F]) SetRaw(sql string, value ...any) *valueSetterImpl[F] {
 	return &valueSetterImpl[F]{
 		field: f.fieldAlias,
@@ -231,3 +267,31 @@ func (f *column[V, F]) ExistsOf(query ormQuery) Clause[F] {
 func (f *column[V, F]) ExistsRaw(sql string, args ...any) Clause[F] {
 	return &ExistsClause[F]{SubQuery: &RawExprClause[F]{sql, args}, Negate: false}
 }
+
+func (f *column[V, F]) Lag(offset int) *WindowExpr[F] {
+	return newFieldWindowExpr[F]("LAG", f.fieldAlias, offset)
+}
+func (f *column[V, F]) Lead(offset int) *WindowExpr[F] {
+	return newFieldWindowExpr[F]("LEAD", f.fieldAlias, offset)
+}
+func (f *column[V, F]) FirstValue() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("FIRST_VALUE", f.fieldAlias)
+}
+func (f *column[V, F]) LastValue() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("LAST_VALUE", f.fieldAlias)
+}
+func (f *column[V, F]) SumOver() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("SUM", f.fieldAlias)
+}
+func (f *column[V, F]) AvgOver() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("AVG", f.fieldAlias)
+}
+func (f *column[V, F]) MinOver() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("MIN", f.fieldAlias)
+}
+func (f *column[V, F]) MaxOver() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("MAX", f.fieldAlias)
+}
+func (f *column[V, F]) CountOver() *WindowExpr[F] {
+	return newFieldWindowExpr[F]("COUNT", f.fieldAlias)
+}
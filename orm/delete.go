@@ -21,6 +21,7 @@ func (q *DeleteQuery[F]) Build() (string, []any) {
 
 	args := make([]any, 0, len(q.whereClauses)*2)
 	q.build(sb, q.tableAlias(), &idx, &args)
+	sb.WriteByte(';')
 	sql := sb.String() // копия в новую строку
 	sbPool.Put(sb)
 	return sql, args
@@ -40,7 +41,6 @@ func (q *DeleteQuery[F]) build(sb *strings.Builder, ta string, paramIndex *int,
 		}
 	}
 	q.buildReturning(sb)
-	sb.WriteByte(';')
 }
 
 func (q *DeleteQuery[F]) Where(clause ...Clause[F]) *DeleteQuery[F] {
@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strings"
+)
+
+// ErrNotFound is returned instead of pgx.ErrNoRows, errors.Is matches both
+var ErrNotFound = errors.New("not found")
+
+// PostgreSQL SQLSTATE codes of integrity constraint violations
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+)
+
+// ConstraintViolation describes violated constraint, Columns are known for
+// constraints declared in proto or named by PostgreSQL defaults
+type ConstraintViolation struct {
+	Table      string
+	Constraint string
+	Columns    []string
+	Err        error
+}
+
+func (e *ConstraintViolation) describe(kind string) string {
+	return fmt.Sprintf("%s violation of %s on %s(%s): %v", kind, e.Constraint, e.Table, strings.Join(e.Columns, ", "), e.Err)
+}
+func (e *ConstraintViolation) Unwrap() error { return e.Err }
+
+type ErrUniqueViolation struct{ ConstraintViolation }
+
+func (e *ErrUniqueViolation) Error() string { return e.describe("unique") }
+
+type ErrForeignKeyViolation struct{ ConstraintViolation }
+
+func (e *ErrForeignKeyViolation) Error() string { return e.describe("foreign key") }
+
+type ErrCheckViolation struct{ ConstraintViolation }
+
+func (e *ErrCheckViolation) Error() string { return e.describe("check") }
+
+func (t *table[F, T]) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s %w: %w", t.alias, ErrNotFound, err)
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	violation := ConstraintViolation{
+		Table:      pgErr.TableName,
+		Constraint: pgErr.ConstraintName,
+		Err:        err,
+	}
+	if violation.Table == "" {
+		violation.Table = t.alias
+	}
+	violation.Columns = t.constraints[violation.Table][violation.Constraint]
+	if len(violation.Columns) == 0 && pgErr.ColumnName != "" {
+		violation.Columns = []string{pgErr.ColumnName}
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return &ErrUniqueViolation{violation}
+	case pgForeignKeyViolation:
+		return &ErrForeignKeyViolation{violation}
+	case pgCheckViolation:
+		return &ErrCheckViolation{violation}
+	}
+	return err
+}
@@ -0,0 +1,406 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ---------------------------------------------------------------------------
+// AIP-160 filtering ----------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterError — filter syntax or validation error, Pos is the byte offset in
+// the filter string
+type FilterError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s: %s at position %d", ErrInvalidFilter, e.Msg, e.Pos)
+}
+func (e *FilterError) Unwrap() error { return ErrInvalidFilter }
+
+type filterKind uint8
+
+const (
+	filterUnsupported filterKind = iota
+	filterString
+	filterInt
+	filterFloat
+	filterBool
+	filterTime
+)
+
+func (k filterKind) String() string {
+	return [...]string{"unsupported", "string", "integer", "number", "boolean", "RFC 3339 timestamp"}[k]
+}
+
+// filterOperand flags are generated from Field.AvailableOperands
+type filterOperand uint8
+
+const (
+	filterEq      filterOperand = 1 << iota // = !=
+	filterCompare                           // < <= > >=
+	filterLike                              // "*" wildcards
+	filterNull                              // = null, :*
+	filterHas                               // array element with ":"
+)
+
+// pathField — column of an API field path, emitted by the generator
+type pathField[F fieldAlias] struct {
+	column   F
+	kind     filterKind
+	operands filterOperand
+}
+
+// withPathFields — API field path to column mapping emitted by the generator
+func (t *table[F, T]) withPathFields(fields map[string]pathField[F]) *table[F, T] {
+	t.pathFields = fields
+	return t
+}
+
+// ParseFilter compiles an AIP-160 filter, e.g. `age > 18 AND email:"*@corp.com"`,
+// into clauses to pass to Where. Fields are API paths ("address.city"),
+// values are bound as parameters. OR binds tighter than AND, "*" is a
+// wildcard in strings, "null" and ":*" test presence of nullable fields and
+// ":" matches an element of array fields. An empty filter returns no clauses.
+func (t *table[F, T]) ParseFilter(filter string) ([]Clause[F], error) {
+	tokens, err := lexFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser[F]{fields: t.pathFields, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+	clauses, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+	return clauses, nil
+}
+
+type filterTokenKind uint8
+
+const (
+	tokenEOF filterTokenKind = iota
+	tokenText
+	tokenString
+	tokenComparator
+	tokenLParen
+	tokenRParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+// filterTimestampRe — unquoted RFC 3339 value, its ':' is not the has operator
+var filterTimestampRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:[Zz]|[+-]\d{2}:\d{2})`)
+
+func isFilterDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()=!<>:"'`, r)
+}
+
+func lexFilter(filter string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0, 16)
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			sb := strings.Builder{}
+			j := i + 1
+			for ; j < len(filter) && filter[j] != c; j++ {
+				if filter[j] == '\\' && j+1 < len(filter) {
+					j++
+				}
+				sb.WriteByte(filter[j])
+			}
+			if j >= len(filter) {
+				return nil, &FilterError{Pos: i, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, filterToken{kind: tokenString, text: sb.String(), pos: i})
+			i = j + 1
+		case strings.ContainsRune("=!<>:", rune(c)):
+			op := string(c)
+			if i+1 < len(filter) && filter[i+1] == '=' && c != '=' && c != ':' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &FilterError{Pos: i, Msg: `unexpected "!", use "!=" or NOT`}
+			}
+			tokens = append(tokens, filterToken{kind: tokenComparator, text: op, pos: i})
+			i += len(op)
+		case len(tokens) > 0 && tokens[len(tokens)-1].kind == tokenComparator && filterTimestampRe.MatchString(filter[i:]):
+			j := len(filterTimestampRe.FindString(filter[i:]))
+			tokens = append(tokens, filterToken{kind: tokenText, text: filter[i : i+j], pos: i})
+			i += j
+		default:
+			j := strings.IndexFunc(filter[i:], isFilterDelimiter)
+			if j < 0 {
+				j = len(filter) - i
+			}
+			tokens = append(tokens, filterToken{kind: tokenText, text: filter[i : i+j], pos: i})
+			i += j
+		}
+	}
+	return append(tokens, filterToken{kind: tokenEOF, pos: len(filter)}), nil
+}
+
+type filterParser[F fieldAlias] struct {
+	fields map[string]pathField[F]
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser[F]) peek() filterToken {
+	return p.tokens[p.pos]
+}
+func (p *filterParser[F]) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+func (p *filterParser[F]) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokenText && tok.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+func (p *filterParser[F]) unexpected(tok filterToken) error {
+	if tok.kind == tokenEOF {
+		return &FilterError{Pos: tok.pos, Msg: "unexpected end of filter"}
+	}
+	return &FilterError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+}
+
+// expression : sequence { AND sequence }
+func (p *filterParser[F]) expression() ([]Clause[F], error) {
+	clauses := make([]Clause[F], 0, 1)
+	for {
+		clause, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+		if !p.keyword("AND") {
+			return clauses, nil
+		}
+	}
+}
+
+// sequence : factor { factor }, adjacent factors are ANDed
+func (p *filterParser[F]) sequence() (Clause[F], error) {
+	clauses := make([]Clause[F], 0, 1)
+	for {
+		clause, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+		tok := p.peek()
+		if tok.kind != tokenLParen && (tok.kind != tokenText || tok.text == "AND" || tok.text == "OR") {
+			break
+		}
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return &AndClause[F]{Clauses: clauses}, nil
+}
+
+// factor : term { OR term }
+func (p *filterParser[F]) factor() (Clause[F], error) {
+	clauses := make([]Clause[F], 0, 1)
+	for {
+		clause, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+		if !p.keyword("OR") {
+			break
+		}
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return &OrClause[F]{Clauses: clauses}, nil
+}
+
+// term : [ NOT | - ] simple
+func (p *filterParser[F]) term() (Clause[F], error) {
+	negate := p.keyword("NOT")
+	if tok := p.peek(); !negate && tok.kind == tokenText && strings.HasPrefix(tok.text, "-") {
+		negate = true
+		if tok.text == "-" {
+			p.pos++
+		} else {
+			p.tokens[p.pos] = filterToken{kind: tokenText, text: tok.text[1:], pos: tok.pos + 1}
+		}
+	}
+	clause, err := p.simple()
+	if err != nil || !negate {
+		return clause, err
+	}
+	return &NotClause[F]{Inner: clause}, nil
+}
+
+// simple : restriction | ( expression )
+func (p *filterParser[F]) simple() (Clause[F], error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		clauses, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.unexpected(closing)
+		}
+		if len(clauses) == 1 {
+			return clauses[0], nil
+		}
+		return &AndClause[F]{Clauses: clauses}, nil
+	case tokenText:
+		return p.restriction(tok)
+	default:
+		return nil, p.unexpected(tok)
+	}
+}
+
+// restriction : field comparator value
+func (p *filterParser[F]) restriction(path filterToken) (Clause[F], error) {
+	field, ok := p.fields[path.text]
+	if !ok {
+		return nil, &FilterError{Pos: path.pos, Msg: fmt.Sprintf("unknown field %q", path.text)}
+	}
+	op := p.next()
+	if op.kind != tokenComparator {
+		return nil, &FilterError{Pos: op.pos, Msg: fmt.Sprintf("expected comparator after %q", path.text)}
+	}
+	arg := p.next()
+	if arg.kind != tokenText && arg.kind != tokenString {
+		return nil, &FilterError{Pos: arg.pos, Msg: fmt.Sprintf("expected value after %q", op.text)}
+	}
+	unsupported := func() error {
+		return &FilterError{Pos: op.pos, Msg: fmt.Sprintf("operator %q is not supported by field %q", op.text, path.text)}
+	}
+	require := func(operand filterOperand) error {
+		if field.operands&operand == 0 {
+			return unsupported()
+		}
+		return nil
+	}
+	fieldClause := func(operator string, right sqlBuilder, negate bool) Clause[F] {
+		return &FieldClause[F]{Field: field.column, Operator: operator, Right: right, Negate: negate}
+	}
+
+	// presence
+	isNull := arg.kind == tokenText && arg.text == "null" && (op.text == "=" || op.text == "!=")
+	if isNull || arg.kind == tokenText && arg.text == "*" && op.text == ":" {
+		if err := require(filterNull); err != nil {
+			return nil, err
+		}
+		if op.text == "=" {
+			return fieldClause("IS NULL", &RawExprClause[F]{SQL: ""}, false), nil
+		}
+		return fieldClause("IS NOT NULL", &RawExprClause[F]{SQL: ""}, false), nil
+	}
+
+	if field.kind == filterUnsupported {
+		return nil, &FilterError{Pos: path.pos, Msg: fmt.Sprintf("field %q can only be tested for null", path.text)}
+	}
+	value, err := parseFilterValue(field.kind, arg.text)
+	if err != nil {
+		return nil, &FilterError{Pos: arg.pos, Msg: fmt.Sprintf("value %q of field %q is not a %s", arg.text, path.text, field.kind)}
+	}
+	pattern, isPattern := value.(string)
+	isPattern = isPattern && field.operands&filterLike != 0 && (op.text == ":" || strings.Contains(pattern, "*"))
+	switch op.text {
+	case ":":
+		if field.operands&filterHas != 0 {
+			return fieldClause("@>", &ParamExprClause[F]{Value: filterElement(value)}, false), nil
+		}
+		if isPattern {
+			return fieldClause("LIKE", &ParamExprClause[F]{Value: likePattern(pattern)}, false), nil
+		}
+		if err = require(filterEq); err != nil {
+			return nil, err
+		}
+		return fieldClause("=", &ParamExprClause[F]{Value: value}, false), nil
+	case "=", "!=":
+		if isPattern {
+			return fieldClause("LIKE", &ParamExprClause[F]{Value: likePattern(pattern)}, op.text == "!="), nil
+		}
+		if err = require(filterEq); err != nil {
+			return nil, err
+		}
+		return fieldClause(op.text, &ParamExprClause[F]{Value: value}, false), nil
+	default:
+		if err = require(filterCompare); err != nil {
+			return nil, err
+		}
+		return fieldClause(op.text, &ParamExprClause[F]{Value: value}, false), nil
+	}
+}
+
+func parseFilterValue(kind filterKind, text string) (any, error) {
+	switch kind {
+	case filterInt:
+		return strconv.ParseInt(text, 10, 64)
+	case filterFloat:
+		return strconv.ParseFloat(text, 64)
+	case filterBool:
+		return strconv.ParseBool(text)
+	case filterTime:
+		return time.Parse(time.RFC3339Nano, text)
+	default:
+		return text, nil
+	}
+}
+
+// filterElement wraps value into a one element array for "@>"
+func filterElement(value any) any {
+	switch v := value.(type) {
+	case int64:
+		return []int64{v}
+	case float64:
+		return []float64{v}
+	case bool:
+		return []bool{v}
+	case time.Time:
+		return []time.Time{v}
+	default:
+		return []string{v.(string)}
+	}
+}
+
+// likePattern escapes LIKE wildcards of the value and maps "*" to "%"
+func likePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`).Replace(value)
+}
@@ -0,0 +1,73 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newTestFilterTable() *table[fieldAlias, testScanner] {
+	return newTestTable("age", "email", "tags", "deleted_at").withPathFields(map[string]pathField[fieldAlias]{
+		"age":        {column: testField("age"), kind: filterInt, operands: filterEq | filterCompare},
+		"email":      {column: testField("email"), kind: filterString, operands: filterEq | filterCompare | filterLike},
+		"tags":       {column: testField("tags"), kind: filterString, operands: filterHas},
+		"deleted_at": {column: testField("deleted_at"), kind: filterTime, operands: filterEq | filterCompare | filterNull},
+	})
+}
+
+func TestParseFilter(t *testing.T) {
+	tb := newTestFilterTable()
+	cases := []struct {
+		filter string
+		sql    string
+		args   string
+	}{
+		{
+			filter: `age > 18 AND email:"*@corp.com"`,
+			sql:    `SELECT 1 FROM users AS users WHERE users.age > $1 AND users.email LIKE $2;`,
+			args:   `[18 %@corp.com]`,
+		},
+		{
+			filter: `age < 10 OR age >= 60 email = "a_b*" -tags:go`,
+			sql: `SELECT 1 FROM users AS users WHERE ((users.age < $1 OR users.age >= $2) AND ` +
+				`users.email LIKE $3 AND NOT (users.tags @> $4));`,
+			args: `[10 60 a\_b% [go]]`,
+		},
+		{
+			filter: `NOT (deleted_at = null OR deleted_at > "2024-01-01T00:00:00Z")`,
+			sql:    `SELECT 1 FROM users AS users WHERE NOT ((users.deleted_at IS NULL  OR users.deleted_at > $1));`,
+			args:   `[2024-01-01 00:00:00 +0000 UTC]`,
+		},
+		{
+			filter: `deleted_at > 2024-01-01T00:00:00Z AND deleted_at <= 2024-02-01T10:30:00.5+03:00`,
+			sql:    `SELECT 1 FROM users AS users WHERE users.deleted_at > $1 AND users.deleted_at <= $2;`,
+			args:   `[2024-01-01 00:00:00 +0000 UTC 2024-02-01 10:30:00.5 +0300 +0300]`,
+		},
+	}
+	for _, c := range cases {
+		clauses, err := tb.ParseFilter(c.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", c.filter, err)
+		}
+		sql, args := tb.Select1().Where(clauses...).Build()
+		if sql != c.sql || fmt.Sprint(args) != c.args {
+			t.Fatalf("%s:\nwant: %s %s\ngot : %s %v", c.filter, c.sql, c.args, sql, args)
+		}
+	}
+
+	for filter, msg := range map[string]string{
+		`name = "x"`:        `unknown field "name" at position 0`,
+		`tags = go`:         `operator "=" is not supported by field "tags" at position 5`,
+		`age = 1 AND age:*`: `operator ":" is not supported by field "age" at position 15`,
+		`age > old`:         `value "old" of field "age" is not a integer at position 6`,
+		`(age > 1`:          `unexpected end of filter at position 8`,
+		`email = "x`:        `unterminated string at position 8`,
+		`age`:               `expected comparator after "age" at position 3`,
+	} {
+		_, err := tb.ParseFilter(filter)
+		var filterErr *FilterError
+		if !errors.As(err, &filterErr) || !errors.Is(err, ErrInvalidFilter) || err.Error() != "invalid filter: "+msg {
+			t.Fatalf("%s: want %q, got %v", filter, msg, err)
+		}
+	}
+}
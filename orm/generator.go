@@ -26,6 +26,7 @@ func (g *GeneratedInfo) Imports() []string {
 		panic("nil in generated info")
 	}
 	idents := append([]string{
+		"context",
 		"google.golang.org/protobuf/proto",
 		"github.com/jackc/pgtype",
 	}, g.protoImports...)
@@ -0,0 +1,221 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"reflect"
+	"slices"
+)
+
+// ---------------------------------------------------------------------------
+// Aggregate graphs -----------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// graphSaver saves related rows of a saved parent row. parent returns column
+// values of the parent by name, entity is the parent message.
+type graphSaver interface {
+	saveGraph(
+		ctx context.Context,
+		db DB,
+		parent func(column string) any,
+		entity protoreflect.Message,
+		deleteOrphans bool,
+	) error
+}
+
+// withGraph is set after all tables are created, so relations may reference
+// tables declared later
+func (t *table[F, T]) withGraph(savers ...graphSaver) *table[F, T] {
+	t.graph = savers
+	return t
+}
+
+func saveGraphRelations(
+	ctx context.Context,
+	db DB,
+	savers []graphSaver,
+	parent func(column string) any,
+	entity protoreflect.Message,
+	deleteOrphans bool,
+) error {
+	for _, saver := range savers {
+		if err := saver.saveGraph(ctx, db, parent, entity, deleteOrphans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rowColumns[F fieldAlias, S targeter[F]](t *table[F, S], row S) func(column string) any {
+	return func(column string) any {
+		for _, f := range t.allFields {
+			if f.String() == column {
+				return relationKey(row.getValue(f)())
+			}
+		}
+		return nil
+	}
+}
+
+// isZeroKey — the row has no primary key value yet, so it is inserted and the
+// database generates the key
+func isZeroKey[F fieldAlias](primaryKey []F, setters []ValueSetter[F]) bool {
+	if len(primaryKey) == 0 {
+		return false
+	}
+	for _, setter := range setters {
+		if !slices.ContainsFunc(primaryKey, func(f F) bool { return f.String() == setter.Column().String() }) {
+			continue
+		}
+		if value := relationKey(setter.Value()); value != nil && !reflect.ValueOf(value).IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// saveRow inserts rows without primary key values and upserts the rest by
+// target, every column is returned
+func saveRow[F fieldAlias, S targeter[F]](
+	ctx context.Context,
+	db DB,
+	t *table[F, S],
+	setters []ValueSetter[F],
+	target []F,
+) (S, error) {
+	query := t.Insert()
+	switch {
+	case isZeroKey(t.primaryKey, setters):
+		query.From(slices.DeleteFunc(slices.Clone(setters), func(s ValueSetter[F]) bool {
+			return slices.ContainsFunc(t.primaryKey, func(f F) bool { return f.String() == s.Column().String() })
+		})...)
+	case len(target) > 0:
+		columns := make([]F, len(setters))
+		for i, setter := range setters {
+			columns[i] = setter.Column()
+		}
+		updates := exceptFields(columns, target)
+		if len(updates) == 0 {
+			updates = target
+		}
+		query.From(setters...).OnConflict(target...).DoUpdate(updates...).DoUpdateSet(t.managedUpdates()...)
+	default:
+		query.From(setters...)
+	}
+	return t.QueryRow(ctx, db, query.ReturningAll())
+}
+
+// copyMessage sets populated fields of src to dst, so generated keys and
+// defaults reach the saved message
+func copyMessage(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		dst.Set(fd, v)
+		return true
+	})
+}
+
+// copyGraph copies the saved clone src back to dst, messages of repeated
+// fields are updated in place, so the caller's children keep their identity
+func copyGraph(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsList() && fd.Message() != nil && dst.Has(fd) && dst.Get(fd).List().Len() == v.List().Len() {
+			list := dst.Get(fd).List()
+			for i := range list.Len() {
+				copyGraph(list.Get(i).Message(), v.List().Get(i).Message())
+			}
+			return true
+		}
+		dst.Set(fd, v)
+		return true
+	})
+}
+
+func relationList(entity protoreflect.Message, field protoreflect.Name) (protoreflect.List, error) {
+	fd := entity.Descriptor().Fields().ByName(field)
+	if fd == nil || !fd.IsList() || fd.Message() == nil {
+		return nil, fmt.Errorf("pgx-orm: save %s: no repeated field in %s", field, entity.Descriptor().FullName())
+	}
+	return entity.Get(fd).List(), nil
+}
+
+// saveGraph upserts children with the parent key set, removes children absent
+// from the field when deleteOrphans
+func (r *Relation[PF, PS, PT, CF, CS, CT]) saveGraph(
+	ctx context.Context,
+	db DB,
+	parent func(column string) any,
+	entity protoreflect.Message,
+	deleteOrphans bool,
+) error {
+	if r.upcast == nil || r.downcast == nil {
+		return fmt.Errorf("pgx-orm: save %s: casters of %s are not set, see WithCasters", r.field, r.table.alias)
+	}
+	list, err := relationList(entity, r.field)
+	if err != nil {
+		return err
+	}
+	parentKey := parent(r.parentKey.String())
+	kept := make([]Clause[CF], 0, list.Len())
+	for i := range list.Len() {
+		child := list.Get(i).Message()
+		setters := GetFieldsSetters(r.downcast(child.Interface().(CT)), r.table.writeFields()...)
+		for j, setter := range setters {
+			if setter.Column().String() == r.childKey.String() {
+				setters[j] = NewValueSetter(r.childKey, parentKey)
+			}
+		}
+		saved, err := saveRow(ctx, db, r.table, setters, r.table.primaryKey)
+		if err != nil {
+			return err
+		}
+		copyMessage(child, r.upcast(saved).ProtoReflect())
+		if len(r.table.primaryKey) > 0 {
+			kept = append(kept, keyClause(r.table.primaryKey, GetFieldsValues(saved, r.table.primaryKey...)))
+		}
+		if err = saveGraphRelations(ctx, db, r.table.graph, rowColumns(r.table, saved), child, deleteOrphans); err != nil {
+			return err
+		}
+	}
+	if !deleteOrphans {
+		return nil
+	}
+	clauses := []Clause[CF]{&FieldClause[CF]{Field: r.childKey, Operator: "=", Right: &ParamExprClause[CF]{Value: parentKey}}}
+	if len(kept) > 0 {
+		clauses = append(clauses, &NotClause[CF]{Inner: &OrClause[CF]{Clauses: kept}})
+	}
+	_, err = r.table.Execute(ctx, db, r.table.deleteQuery(clauses...))
+	return err
+}
+
+// saveGraph links targets of the field to the owner, targets must exist;
+// links of absent targets are removed when deleteOrphans
+func (m *ManyToMany[K, JF, JS, TF, TS, TT]) saveGraph(
+	ctx context.Context,
+	db DB,
+	parent func(column string) any,
+	entity protoreflect.Message,
+	deleteOrphans bool,
+) error {
+	if m.downcast == nil {
+		return fmt.Errorf("pgx-orm: save %s: casters of %s are not set, see WithCasters", m.field, m.target.alias)
+	}
+	list, err := relationList(entity, m.field)
+	if err != nil {
+		return err
+	}
+	targets := make([]K, 0, list.Len())
+	for i := range list.Len() {
+		value := relationKey(m.downcast(list.Get(i).Message().Interface().(TT)).getValue(m.targetPK)())
+		key, ok := value.(K)
+		if !ok {
+			return fmt.Errorf("pgx-orm: save %s: unexpected key %v of %s", m.field, value, m.target.alias)
+		}
+		targets = append(targets, key)
+	}
+	assoc := m.Of(func(context.Context, SqlOpType) DB { return db }, parent(m.ownerPK))
+	if deleteOrphans {
+		return assoc.Replace(ctx, targets...)
+	}
+	return assoc.Add(ctx, targets...)
+}
@@ -0,0 +1,111 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"testing"
+)
+
+type testGraphDB struct {
+	DB
+	returned [][]any
+	sql      []string
+}
+
+type testErrRow struct{ err error }
+
+func (r testErrRow) Scan(...any) error { return r.err }
+
+func (d *testGraphDB) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	d.sql = append(d.sql, sql)
+	if len(d.returned) == 0 {
+		return testErrRow{err: errors.New("no rows left")}
+	}
+	rows := &testRows{data: d.returned[:1]}
+	d.returned = d.returned[1:]
+	rows.Next()
+	return rows
+}
+
+func (d *testGraphDB) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	d.sql = append(d.sql, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func TestRelationSaveGraph(t *testing.T) {
+	children := newTestTable("id", "parent_id").withPrimaryKey(testField("id"))
+	rel := newRelation[fieldAlias, testScanner, *structpb.ListValue, fieldAlias, testScanner, *structpb.Value](
+		children, "values", testField("id"), testField("parent_id"),
+		func(s testScanner) *structpb.Value {
+			return structpb.NewNumberValue(float64(s.getValue(testField("id"))().(int)))
+		},
+		func(v *structpb.Value) testScanner {
+			return testScanner{"id": int(v.GetNumberValue()), "parent_id": nil}
+		},
+	)
+
+	entity := &structpb.ListValue{Values: []*structpb.Value{structpb.NewNumberValue(0), structpb.NewNumberValue(7)}}
+	db := &testGraphDB{returned: [][]any{{11, 1}, {7, 1}}}
+	parent := func(string) any { return 1 }
+	if err := rel.saveGraph(context.Background(), db, parent, entity.ProtoReflect(), true); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"INSERT INTO users (parent_id) VALUES ($1) RETURNING id, parent_id;",
+		"INSERT INTO users (id, parent_id) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET parent_id=EXCLUDED.parent_id RETURNING id, parent_id;",
+		"DELETE FROM users WHERE users.parent_id = $1 AND NOT ((users.id = $2 OR users.id = $3));",
+	}
+	if len(db.sql) != len(want) {
+		t.Fatalf("save queries fail: %q", db.sql)
+	}
+	for i := range want {
+		if db.sql[i] != want[i] {
+			t.Fatalf("save query %d fail:\nwant: %s\ngot : %s", i, want[i], db.sql[i])
+		}
+	}
+	if got := entity.Values[0].GetNumberValue(); got != 11 {
+		t.Fatalf("generated key is not copied back: %v", got)
+	}
+}
+
+func TestSaveGraphCopyBack(t *testing.T) {
+	children := newTestTable("id", "parent_id").withPrimaryKey(testField("id"))
+	rel := newRelation[fieldAlias, testScanner, *structpb.ListValue, fieldAlias, testScanner, *structpb.Value](
+		children, "values", testField("id"), testField("parent_id"),
+		func(s testScanner) *structpb.Value {
+			return structpb.NewNumberValue(float64(s.getValue(testField("id"))().(int)))
+		},
+		func(v *structpb.Value) testScanner {
+			return testScanner{"id": int(v.GetNumberValue()), "parent_id": nil}
+		},
+	)
+	parents := newTable[fieldAlias, testScanner]("parents", func() testScanner { return testScanner{} }, testField("id")).
+		withPrimaryKey(testField("id")).
+		withGraph(rel)
+	db := &testGraphDB{returned: [][]any{{1}, {11, 1}}}
+	repo := newGenericRepository(
+		newGenericScannerRepository(parents, NewDbGetter(db)),
+		func(*structpb.ListValue) testScanner { return testScanner{"id": 0} },
+		func(testScanner) *structpb.ListValue { return &structpb.ListValue{} },
+	)
+
+	entity := &structpb.ListValue{Values: []*structpb.Value{structpb.NewNumberValue(0), structpb.NewNumberValue(0)}}
+	first := entity.Values[0]
+	if err := repo.SaveGraph(context.Background(), entity); err == nil {
+		t.Fatal("save of the second child must fail")
+	}
+	if first.GetNumberValue() != 0 {
+		t.Fatalf("failed save must not change the messages: %v", first)
+	}
+
+	db.returned = [][]any{{1}, {11, 1}, {12, 1}}
+	if err := repo.SaveGraph(context.Background(), entity); err != nil {
+		t.Fatal(err)
+	}
+	if entity.Values[0] != first || first.GetNumberValue() != 11 || entity.Values[1].GetNumberValue() != 12 {
+		t.Fatalf("saved keys are not copied back in place: %v", entity)
+	}
+}
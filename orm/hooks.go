@@ -0,0 +1,108 @@
+package orm
+
+import (
+	"context"
+	"google.golang.org/protobuf/proto"
+)
+
+// ---------------------------------------------------------------------------
+// Repository hooks -----------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// Hook is called by proto repositories with the entity and its scanner, an
+// error aborts the operation and is returned to the caller. Before hooks may
+// change the entity, e.g. generate IDs, it is downcast after them; their
+// scanner is read-only. Batch* methods do not run hooks.
+type Hook[S any, T proto.Message] func(ctx context.Context, entity T, scanner S) error
+
+type hookPoint int
+
+const (
+	hookBeforeInsert hookPoint = iota
+	hookAfterInsert
+	hookBeforeUpdate
+	hookAfterUpdate
+	hookBeforeDelete
+	hookAfterDelete
+	hookAfterLoad
+)
+
+// withHooks appends hooks, so hooks of default options run before the ones
+// of the call
+func withHooks[F fieldAlias, S targeter[F], T proto.Message](point hookPoint, hooks []Hook[S, T]) ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		if opts.hooks == nil {
+			opts.hooks = make(map[hookPoint][]Hook[S, T])
+		}
+		opts.hooks[point] = append(opts.hooks[point], hooks...)
+	})
+}
+
+// BeforeInsert — hooks of Insert*, Upsert* and SaveGraph rows before writing
+func BeforeInsert[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookBeforeInsert, hooks)
+}
+
+// AfterInsert — hooks of written rows, *Ret methods pass the returned row
+func AfterInsert[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookAfterInsert, hooks)
+}
+
+// BeforeUpdate — hooks of Update, UpdateRet, UpdateMasked and UpdateBy*
+func BeforeUpdate[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookBeforeUpdate, hooks)
+}
+
+// AfterUpdate — hooks of updated rows, UpdateRet passes the returned row
+func AfterUpdate[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookAfterUpdate, hooks)
+}
+
+// BeforeDelete — hooks of rows matching Delete, HardDelete and DeleteBy*;
+// with delete hooks the rows are loaded and deleted in one transaction
+func BeforeDelete[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookBeforeDelete, hooks)
+}
+
+// AfterDelete — hooks of deleted rows, see BeforeDelete
+func AfterDelete[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookAfterDelete, hooks)
+}
+
+// AfterLoad — hooks of rows returned by GetBy*, ListBy, ListPage and Stream
+func AfterLoad[F fieldAlias, S targeter[F], T proto.Message](hooks ...Hook[S, T]) ProtoCallOption[F, S, T] {
+	return withHooks[F](hookAfterLoad, hooks)
+}
+
+func (o *protoCallOptions[F, S, T]) hasHooks(points ...hookPoint) bool {
+	for _, point := range points {
+		if len(o.hooks[point]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// runHooks calls hooks of point for every entity, entities and models are
+// index aligned
+func (o *protoCallOptions[F, S, T]) runHooks(ctx context.Context, point hookPoint, entities []T, models []S) error {
+	hooks := o.hooks[point]
+	if len(hooks) == 0 {
+		return nil
+	}
+	for i := range entities {
+		for _, hook := range hooks {
+			if err := hook(ctx, entities[i], models[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (o *protoCallOptions[F, S, T]) runHook(ctx context.Context, point hookPoint, entity T, model S) error {
+	if len(o.hooks[point]) == 0 {
+		return nil
+	}
+	return o.runHooks(ctx, point, []T{entity}, []S{model})
+}
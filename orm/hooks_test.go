@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/protobuf/types/known/structpb"
+	"slices"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	tb := newTestTable("id", "name")
+	db := &testExecDB{}
+	calls := make([]string, 0)
+	hook := func(name string, err error) Hook[testScanner, *structpb.Value] {
+		return func(_ context.Context, entity *structpb.Value, scanner testScanner) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+	repo := newGenericRepository(
+		newGenericScannerRepository(tb, NewDbGetter(db)),
+		func(v *structpb.Value) testScanner { return testScanner{"id": 1, "name": v.GetStringValue()} },
+		func(s testScanner) *structpb.Value { return structpb.NewStringValue(s["name"].(string)) },
+		BeforeInsert[fieldAlias](hook("default", nil)),
+	)
+
+	err := repo.Insert(context.Background(), structpb.NewStringValue("a"),
+		BeforeInsert[fieldAlias](hook("before", nil)),
+		AfterInsert[fieldAlias](hook("after", nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(calls, []string{"default", "before", "after"}) {
+		t.Fatalf("hooks order fail: %v", calls)
+	}
+	if len(db.sql) != 1 {
+		t.Fatalf("insert is not executed: %v", db.sql)
+	}
+
+	abort := errors.New("invalid")
+	err = repo.Update(context.Background(), structpb.NewStringValue("b"), nil, BeforeUpdate[fieldAlias](hook("update", abort)))
+	if !errors.Is(err, abort) || len(db.sql) != 1 {
+		t.Fatalf("before hook must abort the update: %v %v", err, db.sql)
+	}
+}
+
+func TestBeforeHookChangesEntity(t *testing.T) {
+	tb := newTestTable("id", "name")
+	db := &testExecDB{}
+	repo := newGenericRepository(
+		newGenericScannerRepository(tb, NewDbGetter(db)),
+		func(v *structpb.Value) testScanner { return testScanner{"id": 1, "name": v.GetStringValue()} },
+		func(s testScanner) *structpb.Value { return structpb.NewStringValue(s["name"].(string)) },
+	)
+	generate := func(_ context.Context, entity *structpb.Value, scanner testScanner) error {
+		if scanner["name"] != "" {
+			t.Fatalf("scanner must hold the entity before the hook: %v", scanner)
+		}
+		entity.Kind = &structpb.Value_StringValue{StringValue: "generated"}
+		return nil
+	}
+	if err := repo.Insert(context.Background(), structpb.NewStringValue(""), BeforeInsert[fieldAlias](generate)); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.args) != 1 || !slices.Contains(db.args[0], any("generated")) {
+		t.Fatalf("value set by the hook is not written: %v", db.args)
+	}
+}
@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -13,12 +14,21 @@ type InsertQuery[F fieldAlias] struct {
 	baseQuery[F]
 
 	columns []F
-	values  []any // single‑row insert for simplicity; extendable to [][]any
+	rows    [][]any  // VALUES (...), (...); split by chunks() when over maxQueryParams
+	source  ormQuery // INSERT ... SELECT
+	// DEFAULT VALUES
+	defaultValues bool
 
 	// ON CONFLICT handling
-	conflictTarget []F // columns in UNIQUE/PK to target; empty → global
-	doNothing      bool
-	updateAssigns  []F // SET … = … when DO UPDATE used
+	conflictTarget     []F         // columns in UNIQUE/PK to target; empty → global
+	conflictConstraint string      // ON CONFLICT ON CONSTRAINT name
+	conflictWhere      []Clause[F] // index predicate of partial unique index
+	doNothing          bool
+	updateAssigns      []F              // SET c = EXCLUDED.c when DO UPDATE used
+	updateSetters      []ValueSetter[F] // SET c = <expr> when DO UPDATE used
+	updateWhere        []Clause[F]      // DO UPDATE SET ... WHERE
+
+	err error // Rows with different columns, ON CONSTRAINT with WHERE
 }
 
 func (q *InsertQuery[F]) mustOrmQuery() {}
@@ -30,8 +40,9 @@ func (q *InsertQuery[F]) Build() (string, []any) {
 
 	sb.Grow(128 + len(q.columns)*16)
 
-	args := make([]any, 0, len(q.values)+len(q.updateAssigns))
+	args := make([]any, 0, len(q.rows)*len(q.columns)+len(q.updateAssigns))
 	q.build(sb, q.tableAlias(), &idx, &args)
+	sb.WriteByte(';')
 	sql := sb.String() // копия в новую строку
 	sbPool.Put(sb)
 	return sql, args
@@ -42,7 +53,9 @@ func (q *InsertQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 	// INSERT INTO tbl (c1,c2) VALUES ($1,$2)
 	buf.WriteString("INSERT INTO ")
 	buf.WriteString(ta)
-	if len(q.columns) > 0 {
+	if q.defaultValues {
+		buf.WriteString(" DEFAULT VALUES")
+	} else if len(q.columns) > 0 {
 		buf.WriteString(" (")
 		for i, c := range q.columns {
 			if i > 0 {
@@ -53,25 +66,42 @@ func (q *InsertQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 		buf.WriteByte(')')
 	}
 
-	// VALUES
-	buf.WriteString(" VALUES (")
-	for i, v := range q.values {
-		if i > 0 {
-			buf.WriteString(", ")
+	switch {
+	case q.defaultValues:
+	case q.source != nil:
+		buf.WriteByte(' ')
+		q.source.build(buf, q.source.tableAlias(), paramIndex, args)
+	default:
+		buf.WriteString(" VALUES ")
+		for r, row := range q.rows {
+			if r > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteByte('(')
+			for i, v := range row {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				if expr, ok := v.(exprValue); ok {
+					buf.WriteString(string(expr))
+					continue
+				}
+				buf.WriteByte('$')
+				buf.WriteString(strconv.Itoa(*paramIndex))
+				*paramIndex++
+				*args = append(*args, v)
+			}
+			buf.WriteByte(')')
 		}
-		buf.WriteByte('$')
-		buf.WriteString(strconv.Itoa(*paramIndex))
-		*paramIndex++
-		vals := *args
-		vals = append(vals, v)
-		*args = vals
 	}
-	buf.WriteByte(')')
 
 	// ON CONFLICT
-	if q.doNothing || len(q.updateAssigns) > 0 {
+	if q.doNothing || len(q.updateAssigns) > 0 || len(q.updateSetters) > 0 {
 		buf.WriteString(" ON CONFLICT")
-		if len(q.conflictTarget) > 0 {
+		if q.conflictConstraint != "" {
+			buf.WriteString(" ON CONSTRAINT ")
+			buf.WriteString(q.conflictConstraint)
+		} else if len(q.conflictTarget) > 0 {
 			buf.WriteString(" (")
 			for i, c := range q.conflictTarget {
 				if i > 0 {
@@ -80,6 +110,7 @@ func (q *InsertQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 				buf.WriteString(c.String())
 			}
 			buf.WriteByte(')')
+			buildClauses(buf, " WHERE ", q.conflictWhere, ta, paramIndex, args)
 		}
 		if q.doNothing {
 			buf.WriteString(" DO NOTHING")
@@ -91,12 +122,18 @@ func (q *InsertQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 				}
 				buf.WriteString(asg.String() + "=EXCLUDED." + asg.String())
 			}
+			for i, setter := range q.updateSetters {
+				if i > 0 || len(q.updateAssigns) > 0 {
+					buf.WriteString(", ")
+				}
+				setter.build(buf, ta, paramIndex, args)
+			}
+			buildClauses(buf, " WHERE ", q.updateWhere, ta, paramIndex, args)
 		}
 	}
 
 	// RETURNING
 	q.buildReturning(buf)
-	buf.WriteByte(';')
 }
 
 func (q *InsertQuery[F]) Columns(columns ...F) *InsertQuery[F] {
@@ -104,7 +141,13 @@ func (q *InsertQuery[F]) Columns(columns ...F) *InsertQuery[F] {
 	return q
 }
 func (q *InsertQuery[F]) Values(values ...any) *InsertQuery[F] {
-	q.values = values
+	q.rows = [][]any{values}
+	return q
+}
+
+// ValuesRows — multi-row VALUES, each row in Columns order
+func (q *InsertQuery[F]) ValuesRows(rows [][]any) *InsertQuery[F] {
+	q.rows = rows
 	return q
 }
 func (q *InsertQuery[F]) From(setters ...ValueSetter[F]) *InsertQuery[F] {
@@ -112,16 +155,87 @@ func (q *InsertQuery[F]) From(setters ...ValueSetter[F]) *InsertQuery[F] {
 	vals := make([]any, 0, len(setters))
 	for _, setter := range setters {
 		cols = append(cols, setter.Column())
-		vals = append(vals, setter.Value())
+		vals = append(vals, insertValue(setter))
 	}
 	q.Columns(cols...)
 	q.Values(vals...)
 	return q
 }
+
+// Rows — multi-row variant of From, every row must set the columns of the
+// first row in the same order, otherwise the query fails with ErrRowsMismatch
+func (q *InsertQuery[F]) Rows(rows ...[]ValueSetter[F]) *InsertQuery[F] {
+	if len(rows) == 0 {
+		return q
+	}
+	cols := make([]F, 0, len(rows[0]))
+	for _, setter := range rows[0] {
+		cols = append(cols, setter.Column())
+	}
+	values := make([][]any, 0, len(rows))
+	for r, row := range rows {
+		if len(row) != len(cols) {
+			q.err = fmt.Errorf("pgx-orm: insert into %s: row %d has %d columns, want %d: %w", q.ta, r, len(row), len(cols), ErrRowsMismatch)
+			return q
+		}
+		vals := make([]any, 0, len(row))
+		for i, setter := range row {
+			if setter.Column().String() != cols[i].String() {
+				q.err = fmt.Errorf("pgx-orm: insert into %s: row %d sets %s at %s: %w", q.ta, r, setter.Column(), cols[i], ErrRowsMismatch)
+				return q
+			}
+			vals = append(vals, insertValue(setter))
+		}
+		values = append(values, vals)
+	}
+	q.Columns(cols...)
+	q.ValuesRows(values)
+	return q
+}
+
+// Err — misuse of the builder, checked by tables before sending the query
+func (q *InsertQuery[F]) Err() error {
+	return q.err
+}
+
+// FromSelect — INSERT INTO t (Columns) SELECT ..., the select shares the
+// parameters numbering with the insert
+func (q *InsertQuery[F]) FromSelect(query ormQuery) *InsertQuery[F] {
+	q.source = query
+	return q
+}
+
+// DefaultValues — INSERT INTO t DEFAULT VALUES, every column takes its default
+func (q *InsertQuery[F]) DefaultValues() *InsertQuery[F] {
+	q.defaultValues = true
+	return q
+}
 func (q *InsertQuery[F]) OnConflict(columns ...F) *InsertQuery[F] {
 	q.conflictTarget = columns
 	return q
 }
+
+// OnConflictConstraint — ON CONFLICT ON CONSTRAINT name, takes precedence over
+// OnConflict columns, can't be combined with OnConflictWhere
+func (q *InsertQuery[F]) OnConflictConstraint(name string) *InsertQuery[F] {
+	q.conflictConstraint = name
+	return q.checkConflictWhere()
+}
+
+// OnConflictWhere — index predicate, required to infer a partial unique index
+// of OnConflict columns
+func (q *InsertQuery[F]) OnConflictWhere(clause ...Clause[F]) *InsertQuery[F] {
+	q.conflictWhere = append(q.conflictWhere, clause...)
+	return q.checkConflictWhere()
+}
+
+// checkConflictWhere — PostgreSQL has no index predicate for ON CONSTRAINT
+func (q *InsertQuery[F]) checkConflictWhere() *InsertQuery[F] {
+	if q.err == nil && q.conflictConstraint != "" && len(q.conflictWhere) > 0 {
+		q.err = fmt.Errorf("pgx-orm: insert into %s: on conflict on constraint %s with where: %w", q.ta, q.conflictConstraint, ErrInvalidConflict)
+	}
+	return q
+}
 func (q *InsertQuery[F]) DoNothing() *InsertQuery[F] {
 	q.doNothing = true
 	return q
@@ -130,6 +244,19 @@ func (q *InsertQuery[F]) DoUpdate(assign ...F) *InsertQuery[F] {
 	q.updateAssigns = assign
 	return q
 }
+
+// DoUpdateSet — DO UPDATE SET with arbitrary setters, e.g.
+// Counter.SetExpr("posts.counter + EXCLUDED.counter")
+func (q *InsertQuery[F]) DoUpdateSet(setters ...ValueSetter[F]) *InsertQuery[F] {
+	q.updateSetters = append(q.updateSetters, setters...)
+	return q
+}
+
+// DoUpdateWhere — rows not matching the condition are left untouched
+func (q *InsertQuery[F]) DoUpdateWhere(clause ...Clause[F]) *InsertQuery[F] {
+	q.updateWhere = append(q.updateWhere, clause...)
+	return q
+}
 func (q *InsertQuery[F]) Returning(fields ...F) *InsertQuery[F] {
 	q.usingFields = fields
 	return q
@@ -138,3 +265,55 @@ func (q *InsertQuery[F]) ReturningAll() *InsertQuery[F] {
 	q.usingFields = q.allFields
 	return q
 }
+
+// chunks splits multi-row insert into queries that fit PostgreSQL
+// parameters limit, RETURNING rows of chunks keep the rows order
+func (q *InsertQuery[F]) chunks() []ormQuery {
+	if len(q.rows) < 2 {
+		return []ormQuery{q}
+	}
+	_, args := q.Build()
+	if len(args) <= maxQueryParams {
+		return []ormQuery{q}
+	}
+	rowParams, perRow := 0, 1
+	for _, row := range q.rows {
+		n := 0
+		for _, v := range row {
+			if _, ok := v.(exprValue); !ok {
+				n++
+			}
+		}
+		rowParams += n
+		perRow = max(perRow, n)
+	}
+	perChunk := max((maxQueryParams-(len(args)-rowParams))/perRow, 1)
+	ret := make([]ormQuery, 0, len(q.rows)/perChunk+1)
+	for i := 0; i < len(q.rows); i += perChunk {
+		part := *q
+		part.rows = q.rows[i:min(i+perChunk, len(q.rows))]
+		ret = append(ret, &part)
+	}
+	return ret
+}
+
+// exprValue is a VALUES item rendered as is, e.g. DEFAULT
+type exprValue string
+
+func insertValue[F fieldAlias](setter ValueSetter[F]) any {
+	if s, ok := setter.(*valueSetterImpl[F]); ok && s.expr != "" {
+		return exprValue(s.expr)
+	}
+	return setter.Value()
+}
+
+func buildClauses[F fieldAlias](buf *strings.Builder, prefix string, clauses []Clause[F], ta string, paramIndex *int, args *[]any) {
+	for i, cl := range clauses {
+		if i == 0 {
+			buf.WriteString(prefix)
+		} else {
+			buf.WriteString(" AND ")
+		}
+		cl.build(buf, ta, paramIndex, args)
+	}
+}
@@ -0,0 +1,168 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strings"
+	"testing"
+)
+
+func newTestInsert() *InsertQuery[fieldAlias] {
+	return &InsertQuery[fieldAlias]{baseQuery: baseQuery[fieldAlias]{ta: "posts"}}
+}
+
+func TestInsertRows(t *testing.T) {
+	sql, args := newTestInsert().
+		Columns(testField("id"), testField("title")).
+		ValuesRows([][]any{{1, "a"}, {2, "b"}, {3, "c"}}).
+		Returning(testField("id")).
+		Build()
+	want := "INSERT INTO posts (id, title) VALUES ($1, $2), ($3, $4), ($5, $6) RETURNING id;"
+	if sql != want {
+		t.Fatalf("multi-row insert fail:\nwant: %s\ngot : %s", want, sql)
+	}
+	if len(args) != 6 || args[4] != 3 || args[5] != "c" {
+		t.Fatalf("args mismatch: %v", args)
+	}
+}
+
+func TestInsertChunks(t *testing.T) {
+	rows := make([][]any, 40000)
+	for i := range rows {
+		rows[i] = []any{i, "t"}
+	}
+	q := newTestInsert().Columns(testField("id"), testField("title")).ValuesRows(rows)
+	parts := q.chunks()
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(parts))
+	}
+	total := 0
+	for _, part := range parts {
+		sql, args := part.Build()
+		if len(args) > maxQueryParams {
+			t.Fatalf("chunk exceeds params limit: %d", len(args))
+		}
+		if !strings.HasPrefix(sql, "INSERT INTO posts (id, title) VALUES ($1, $2), ") {
+			t.Fatalf("chunk numbering fail: %s", sql[:60])
+		}
+		if args[0] != total {
+			t.Fatalf("chunk order fail: first id %v, want %d", args[0], total)
+		}
+		total += len(args) / 2
+	}
+	if total != len(rows) {
+		t.Fatalf("rows lost: %d", total)
+	}
+	if len(newTestInsert().Columns(testField("id")).ValuesRows(rows[:2]).chunks()) != 1 {
+		t.Fatal("small insert must not be split")
+	}
+}
+
+func TestInsertSelectAndDefaults(t *testing.T) {
+	src := newTestSelect(testField("id"), testField("title")).Where(&FieldClause[fieldAlias]{
+		Field: testField("user_id"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 7},
+	})
+	sql, args := newTestInsert().Columns(testField("id"), testField("title")).FromSelect(src).Build()
+	want := "INSERT INTO posts (id, title) SELECT posts.id, posts.title FROM posts AS posts WHERE posts.user_id = $1;"
+	if sql != want || len(args) != 1 {
+		t.Fatalf("insert select fail:\nwant: %s\ngot : %s %v", want, sql, args)
+	}
+
+	sql, _ = newTestInsert().DefaultValues().ReturningAll().Build()
+	if !strings.HasPrefix(sql, "INSERT INTO posts DEFAULT VALUES") {
+		t.Fatalf("default values fail: %s", sql)
+	}
+
+	createdAt := newColumn[string, fieldAlias](testField("created_at"))
+	title := newColumn[string, fieldAlias](testField("title"))
+	sql, args = newTestInsert().Rows(
+		[]ValueSetter[fieldAlias]{title.Set("a"), createdAt.SetDefault()},
+		[]ValueSetter[fieldAlias]{title.Set("b"), createdAt.SetDefault()},
+	).Build()
+	want = "INSERT INTO posts (title, created_at) VALUES ($1, DEFAULT), ($2, DEFAULT);"
+	if sql != want || len(args) != 2 || args[1] != "b" {
+		t.Fatalf("default setter fail:\nwant: %s\ngot : %s %v", want, sql, args)
+	}
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	counter := newColumn[int64, fieldAlias](testField("counter"))
+	title := newColumn[string, fieldAlias](testField("title"))
+	deleted := &FieldClause[fieldAlias]{Field: testField("deleted"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: false}}
+	sql, args := newTestInsert().From(title.Set("a"), counter.Set(1)).
+		OnConflict(testField("title")).
+		OnConflictWhere(deleted).
+		DoUpdateSet(counter.SetExpr("posts.counter + EXCLUDED.counter"), title.SetExcluded()).
+		DoUpdateWhere(&FieldClause[fieldAlias]{Field: testField("counter"), Operator: "<", Right: &ParamExprClause[fieldAlias]{Value: 100}}).
+		Build()
+	want := "INSERT INTO posts (title, counter) VALUES ($1, $2) ON CONFLICT (title) WHERE posts.deleted = $3 " +
+		"DO UPDATE SET counter = posts.counter + EXCLUDED.counter, title = EXCLUDED.title WHERE posts.counter < $4;"
+	if sql != want {
+		t.Fatalf("on conflict fail:\nwant: %s\ngot : %s", want, sql)
+	}
+	if len(args) != 4 || args[3] != 100 {
+		t.Fatalf("args mismatch: %v", args)
+	}
+
+	sql, _ = newTestInsert().From(title.Set("a")).OnConflictConstraint("posts_title_key").DoNothing().Build()
+	want = "INSERT INTO posts (title) VALUES ($1) ON CONFLICT ON CONSTRAINT posts_title_key DO NOTHING;"
+	if sql != want {
+		t.Fatalf("on constraint fail:\nwant: %s\ngot : %s", want, sql)
+	}
+
+	query := newTestInsert().From(title.Set("a")).OnConflictWhere(deleted).OnConflictConstraint("posts_title_key").DoNothing()
+	if !errors.Is(query.Err(), ErrInvalidConflict) {
+		t.Fatalf("expected ErrInvalidConflict, got %v", query.Err())
+	}
+}
+
+type testChunkTx struct {
+	*testTx
+	exec *testExecDB
+}
+
+func (tx *testChunkTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return tx.exec.Exec(ctx, sql, args...)
+}
+
+type testChunkDB struct {
+	testExecDB
+	log []string
+}
+
+func (d *testChunkDB) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	d.log = append(d.log, "begin")
+	return &testChunkTx{testTx: &testTx{log: &d.log}, exec: &d.testExecDB}, nil
+}
+
+func TestInsertRowsMismatch(t *testing.T) {
+	tb := newTestTable("id", "title")
+	id := newColumn[int, fieldAlias](testField("id"))
+	title := newColumn[string, fieldAlias](testField("title"))
+	db := &testChunkDB{}
+	query := tb.Insert().Rows(
+		[]ValueSetter[fieldAlias]{id.Set(1), title.Set("a")},
+		[]ValueSetter[fieldAlias]{title.Set("b"), id.Set(2)},
+	)
+	if _, err := tb.Execute(context.Background(), db, query); !errors.Is(err, ErrRowsMismatch) || len(db.sql) != 0 {
+		t.Fatalf("misaligned rows must fail before sending: %v %v", err, db.sql)
+	}
+	query = tb.Insert().Rows([]ValueSetter[fieldAlias]{id.Set(1), title.Set("a")}, []ValueSetter[fieldAlias]{id.Set(2)})
+	if !errors.Is(query.Err(), ErrRowsMismatch) {
+		t.Fatalf("short row must fail: %v", query.Err())
+	}
+
+	rows := make([][]any, 40000)
+	for i := range rows {
+		rows[i] = []any{i, "t"}
+	}
+	if _, err := tb.Execute(context.Background(), db, tb.Insert().Columns(id, title).ValuesRows(rows)); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.sql) != 2 || fmt.Sprint(db.log) != "[begin commit rollback]" {
+		t.Fatalf("chunks must run in one transaction: %d %v", len(db.sql), db.log)
+	}
+}
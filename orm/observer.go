@@ -0,0 +1,139 @@
+package orm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Query observer -------------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+type QueryOperation string
+
+const (
+	OperationQuery    QueryOperation = "query"
+	OperationQueryRow QueryOperation = "query_row"
+	OperationExec     QueryOperation = "exec"
+	OperationCopy     QueryOperation = "copy"
+)
+
+// QueryEvent describes one statement of a table, Duration, Rows and Err are
+// set before QueryEnd
+type QueryEvent struct {
+	Table     string
+	Operation QueryOperation
+	SQL       string
+	Args      int
+	Start     time.Time
+	Duration  time.Duration
+	// Rows — scanned rows of queries, affected rows of exec and copy
+	Rows int64
+	Err  error
+}
+
+// Observer is notified about statements sent by tables: Query, QueryRow,
+// Execute, CopyFrom, Exists and Iter. The context returned by QueryStart is
+// used for the statement and passed to QueryEnd, e.g. to carry a span.
+// Batches are not observed.
+type Observer interface {
+	QueryStart(ctx context.Context, event *QueryEvent) context.Context
+	QueryEnd(ctx context.Context, event *QueryEvent)
+}
+
+type observerHolder struct{ observer Observer }
+
+var globalObserver atomic.Pointer[observerHolder]
+
+// SetObserver registers the observer of DBs not wrapped by ObservedDbGetter,
+// nil disables it
+func SetObserver(observer Observer) {
+	if observer == nil {
+		globalObserver.Store(nil)
+		return
+	}
+	globalObserver.Store(&observerHolder{observer: observer})
+}
+
+// observedDB carries the observer of ObservedDbGetter to tables
+type observedDB struct {
+	DB
+	observer Observer
+}
+
+// ObservedDbGetter reports statements run over DBs of getter to observer
+// instead of the global one
+func ObservedDbGetter(getter DbGetter, observer Observer) DbGetter {
+	return func(ctx context.Context, operation SqlOpType) DB {
+		return withObserver(getter(ctx, operation), observer)
+	}
+}
+
+func withObserver(db DB, observer Observer) DB {
+	if observer == nil || db == nil {
+		return db
+	}
+	return &observedDB{DB: db, observer: observer}
+}
+
+// unobserved unwraps db of ObservedDbGetter, observer is nil for other DBs
+func unobserved(db DB) (DB, Observer) {
+	if o, ok := db.(*observedDB); ok {
+		return o.DB, o.observer
+	}
+	return db, nil
+}
+
+// observe starts the event of a statement of the table and returns the db to
+// run it on and the func reporting its result
+func (t *table[F, T]) observe(
+	ctx context.Context,
+	db DB,
+	operation QueryOperation,
+	sql string,
+	args int,
+) (context.Context, DB, func(rows int64, err error)) {
+	db, observer := unobserved(db)
+	if observer == nil {
+		holder := globalObserver.Load()
+		if holder == nil {
+			return ctx, db, func(int64, error) {}
+		}
+		observer = holder.observer
+	}
+	event := &QueryEvent{Table: t.alias, Operation: operation, SQL: sql, Args: args, Start: time.Now()}
+	ctx = observer.QueryStart(ctx, event)
+	return ctx, db, func(rows int64, err error) {
+		event.Duration, event.Rows, event.Err = time.Since(event.Start), rows, err
+		observer.QueryEnd(ctx, event)
+	}
+}
+
+// rowCount of QueryRow
+func rowCount(err error) int64 {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) QueryStart(ctx context.Context, event *QueryEvent) context.Context {
+	for _, o := range m {
+		ctx = o.QueryStart(ctx, event)
+	}
+	return ctx
+}
+
+func (m multiObserver) QueryEnd(ctx context.Context, event *QueryEvent) {
+	for _, o := range m {
+		o.QueryEnd(ctx, event)
+	}
+}
+
+// Observers notifies every observer in order
+func Observers(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
@@ -0,0 +1,61 @@
+package orm
+
+import (
+	"context"
+	"testing"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]any
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)              { s.attrs["error"] = err }
+func (s *testSpan) End()                               { s.ended = true }
+
+type testTracer struct{ spans []*testSpan }
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+type testObserver struct{ events []QueryEvent }
+
+func (o *testObserver) QueryStart(ctx context.Context, _ *QueryEvent) context.Context { return ctx }
+func (o *testObserver) QueryEnd(_ context.Context, e *QueryEvent)                     { o.events = append(o.events, *e) }
+
+func TestObserver(t *testing.T) {
+	tb := newTestTable("id", "name")
+	db := &testExecDB{}
+	global, local := &testObserver{}, &testObserver{}
+	tracer := &testTracer{}
+	SetObserver(global)
+	defer SetObserver(nil)
+	getter := ObservedDbGetter(NewDbGetter(db), Observers(local, TracerObserver(tracer)))
+
+	query := tb.Delete().Where(&FieldClause[fieldAlias]{Field: testField("id"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}})
+	if _, err := tb.Execute(context.Background(), getter(context.Background(), SqlMutation), query); err != nil {
+		t.Fatal(err)
+	}
+	if len(global.events) != 0 || len(local.events) != 1 {
+		t.Fatalf("statement must be reported to the getter observer only: %d %d", len(global.events), len(local.events))
+	}
+	e := local.events[0]
+	if e.Table != "users" || e.Operation != OperationExec || e.SQL != db.sql[0] || e.Args != 1 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if len(tracer.spans) != 1 || tracer.spans[0].name != "exec users" || !tracer.spans[0].ended {
+		t.Fatalf("span is not recorded: %+v", tracer.spans)
+	}
+
+	if _, err := tb.Execute(context.Background(), db, query); err != nil {
+		t.Fatal(err)
+	}
+	if len(global.events) != 1 {
+		t.Fatalf("statement of a plain db must be reported globally: %d", len(global.events))
+	}
+}
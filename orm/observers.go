@@ -0,0 +1,120 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"log/slog"
+)
+
+// ---------------------------------------------------------------------------
+// Observer adapters ----------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// failed — ErrNoRows of QueryRow is a result, not a failure
+func (e *QueryEvent) failed() bool {
+	return e.Err != nil && !errors.Is(e.Err, pgx.ErrNoRows)
+}
+
+type zapObserver struct{ logger *zap.Logger }
+
+// ZapObserver logs statements at debug level, failed ones at error level
+func ZapObserver(logger *zap.Logger) Observer {
+	return &zapObserver{logger: logger}
+}
+
+func (o *zapObserver) QueryStart(ctx context.Context, _ *QueryEvent) context.Context { return ctx }
+
+func (o *zapObserver) QueryEnd(_ context.Context, e *QueryEvent) {
+	fields := []zap.Field{
+		zap.String("table", e.Table),
+		zap.String("operation", string(e.Operation)),
+		zap.String("sql", e.SQL),
+		zap.Int("args", e.Args),
+		zap.Duration("duration", e.Duration),
+		zap.Int64("rows", e.Rows),
+	}
+	if e.Err != nil {
+		fields = append(fields, zap.Error(e.Err))
+	}
+	if e.failed() {
+		o.logger.Error("pgx-orm query", fields...)
+		return
+	}
+	o.logger.Debug("pgx-orm query", fields...)
+}
+
+type slogObserver struct{ logger *slog.Logger }
+
+// SlogObserver logs statements at debug level, failed ones at error level
+func SlogObserver(logger *slog.Logger) Observer {
+	return &slogObserver{logger: logger}
+}
+
+func (o *slogObserver) QueryStart(ctx context.Context, _ *QueryEvent) context.Context { return ctx }
+
+func (o *slogObserver) QueryEnd(ctx context.Context, e *QueryEvent) {
+	attrs := []slog.Attr{
+		slog.String("table", e.Table),
+		slog.String("operation", string(e.Operation)),
+		slog.String("sql", e.SQL),
+		slog.Int("args", e.Args),
+		slog.Duration("duration", e.Duration),
+		slog.Int64("rows", e.Rows),
+	}
+	level := slog.LevelDebug
+	if e.Err != nil {
+		attrs = append(attrs, slog.Any("error", e.Err))
+	}
+	if e.failed() {
+		level = slog.LevelError
+	}
+	o.logger.LogAttrs(ctx, level, "pgx-orm query", attrs...)
+}
+
+// Span is the part of an OpenTelemetry trace.Span used by TracerObserver, so
+// the orm needs no otel dependency and spans can be recorded without a
+// collector
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans of statements, e.g. a wrapper of otel trace.Tracer
+// starting client spans
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type spanKey struct{}
+
+type tracerObserver struct{ tracer Tracer }
+
+// TracerObserver starts a span per statement named "<operation> <table>" with
+// OpenTelemetry database attributes
+func TracerObserver(tracer Tracer) Observer {
+	return &tracerObserver{tracer: tracer}
+}
+
+func (o *tracerObserver) QueryStart(ctx context.Context, e *QueryEvent) context.Context {
+	ctx, span := o.tracer.Start(ctx, string(e.Operation)+" "+e.Table)
+	span.SetAttribute("db.system.name", "postgresql")
+	span.SetAttribute("db.collection.name", e.Table)
+	span.SetAttribute("db.operation.name", string(e.Operation))
+	span.SetAttribute("db.query.text", e.SQL)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (o *tracerObserver) QueryEnd(ctx context.Context, e *QueryEvent) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttribute("db.response.returned_rows", e.Rows)
+	if e.failed() {
+		span.RecordError(e.Err)
+	}
+	span.End()
+}
@@ -0,0 +1,68 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// AIP-132 ordering -----------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+var ErrInvalidOrderBy = errors.New("invalid order_by")
+
+// ParseOrderBy parses an AIP-132 order_by, e.g. "name desc, created_at", into
+// terms for SelectQuery.SetOrderTerms. Fields are API paths of scalar columns,
+// each may be followed by "asc" or "desc" and "nulls first" or "nulls last".
+// An empty string returns no terms.
+func (t *table[F, T]) ParseOrderBy(orderBy string) ([]OrderTerm[F], error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+	items := strings.Split(orderBy, ",")
+	terms := make([]OrderTerm[F], 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		words := strings.Fields(item)
+		if len(words) == 0 {
+			return nil, fmt.Errorf("%w: empty item in %q", ErrInvalidOrderBy, orderBy)
+		}
+		path := words[0]
+		field, ok := t.pathFields[path]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidOrderBy, path)
+		}
+		if field.operands&filterCompare == 0 {
+			return nil, fmt.Errorf("%w: field %q can not be ordered", ErrInvalidOrderBy, path)
+		}
+		if seen[path] {
+			return nil, fmt.Errorf("%w: field %q is repeated", ErrInvalidOrderBy, path)
+		}
+		seen[path] = true
+		term := Asc(field.column)
+		words = words[1:]
+		if len(words) > 0 {
+			switch strings.ToLower(words[0]) {
+			case "asc":
+				words = words[1:]
+			case "desc":
+				term.Desc = true
+				words = words[1:]
+			}
+		}
+		if len(words) == 2 && strings.EqualFold(words[0], "nulls") {
+			switch strings.ToLower(words[1]) {
+			case "first":
+				term.Nulls, words = NullsFirst, nil
+			case "last":
+				term.Nulls, words = NullsLast, nil
+			}
+		}
+		if len(words) > 0 {
+			return nil, fmt.Errorf("%w: unexpected %q after field %q", ErrInvalidOrderBy, strings.Join(words, " "), path)
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
@@ -0,0 +1,26 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseOrderBy(t *testing.T) {
+	tb := newTestFilterTable()
+	terms, err := tb.ParseOrderBy(" deleted_at desc nulls last,age ,email ASC NULLS FIRST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := tb.Select1()
+	q.SetOrderTerms(terms...)
+	sql, _ := q.Build()
+	want := "SELECT 1 FROM users AS users ORDER BY users.deleted_at DESC NULLS LAST, users.age ASC, users.email ASC NULLS FIRST;"
+	if sql != want {
+		t.Fatalf("order by fail:\nwant: %s\ngot : %s", want, sql)
+	}
+	for _, orderBy := range []string{"name", "tags", "age sideways", "age, age desc", "age,"} {
+		if _, err = tb.ParseOrderBy(orderBy); !errors.Is(err, ErrInvalidOrderBy) {
+			t.Fatalf("%q: want ErrInvalidOrderBy, got %v", orderBy, err)
+		}
+	}
+}
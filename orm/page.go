@@ -0,0 +1,272 @@
+package orm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Keyset pagination ----------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+var pageTokenKey = newPageTokenKey()
+
+func newPageTokenKey() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// SetPageTokenKey sets HMAC key of page tokens. The default key is random per
+// process, set a shared one when tokens are passed between instances.
+func SetPageTokenKey(key []byte) {
+	pageTokenKey = key
+}
+
+// keysetClause — rows after values in terms order:
+// (a, b) > ($1, $2) for one direction, (a > $1 OR a = $1 AND b < $2) for mixed.
+// NULLs are placed by the NULLS order of terms, so the row comparison is used
+// only when no NULL can sort after the cursor: values are not NULL and terms
+// are NULLS FIRST or notNull columns.
+type keysetClause[F fieldAlias] struct {
+	terms   []OrderTerm[F]
+	values  []any
+	notNull []F
+}
+
+// nullsFirst — effective NULLS order of the term
+func nullsFirst[F fieldAlias](term OrderTerm[F]) bool {
+	return term.Nulls == NullsFirst || term.Nulls == NullsDefault && term.Desc
+}
+
+// nullsBefore reports whether no NULL of the term sorts after a cursor value
+func (c *keysetClause[F]) nullsBefore(term OrderTerm[F]) bool {
+	return nullsFirst(term) || len(exceptFields([]F{term.Field}, c.notNull)) == 0
+}
+
+func (c *keysetClause[F]) mustClauseAlias(F) {}
+func (c *keysetClause[F]) build(buf *strings.Builder, ta string, paramIndex *int, args *[]any) {
+	terms := c.terms[:min(len(c.terms), len(c.values))]
+	params := make([]string, len(terms))
+	uniform := true
+	for i, term := range terms {
+		uniform = uniform && term.Desc == terms[0].Desc && c.values[i] != nil && c.nullsBefore(term)
+		if c.values[i] == nil {
+			continue
+		}
+		params[i] = "$" + strconv.Itoa(*paramIndex)
+		*paramIndex++
+		*args = append(*args, c.values[i])
+	}
+	operator := func(term OrderTerm[F]) string {
+		if term.Desc {
+			return " < "
+		}
+		return " > "
+	}
+	if uniform {
+		buf.WriteByte('(')
+		for i, term := range terms {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(ta)
+			buf.WriteByte('.')
+			buf.WriteString(term.Field.String())
+		}
+		buf.WriteString(")")
+		buf.WriteString(operator(terms[0]))
+		buf.WriteString("(")
+		buf.WriteString(strings.Join(params, ", "))
+		buf.WriteByte(')')
+		return
+	}
+	// after — rows after the cursor value of the term, empty when none are
+	after := func(i int, term OrderTerm[F], column string) string {
+		switch {
+		case params[i] == "" && nullsFirst(term):
+			return column + " IS NOT NULL"
+		case params[i] == "":
+			return ""
+		case c.nullsBefore(term):
+			return column + operator(term) + params[i]
+		default:
+			return column + operator(term) + params[i] + " OR " + column + " IS NULL"
+		}
+	}
+	closing := 0
+	for i, term := range terms {
+		column := ta + "." + term.Field.String()
+		next := after(i, term, column)
+		if i == len(terms)-1 {
+			if next == "" {
+				next = "FALSE"
+			} else if strings.Contains(next, " OR ") {
+				next = "(" + next + ")"
+			}
+			buf.WriteString(next)
+			break
+		}
+		equal := column + " = " + params[i]
+		if params[i] == "" {
+			equal = column + " IS NULL"
+		}
+		buf.WriteByte('(')
+		closing++
+		if next != "" {
+			buf.WriteString(next + " OR ")
+		}
+		buf.WriteString(equal + " AND ")
+	}
+	buf.WriteString(strings.Repeat(")", closing))
+}
+
+// pageSignature binds tokens to the query, so a token of another filter or
+// ordering is rejected
+func pageSignature(query ormQuery) []byte {
+	sql, args := query.Build()
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s%v", sql, args))
+	return sum[:]
+}
+
+func pageTokenMac(signature, payload []byte) []byte {
+	mac := hmac.New(sha256.New, pageTokenKey)
+	mac.Write(signature)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// encodePageToken — cursor values in PostgreSQL text format, pgx sends
+// strings as text parameters of any type
+func encodePageToken(signature []byte, values []any) (string, error) {
+	m := pgtype.NewMap()
+	texts := make([]*string, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		typ, ok := m.TypeForValue(value)
+		if !ok {
+			return "", fmt.Errorf("pgx-orm: cannot encode cursor value of type %T", value)
+		}
+		buf, err := m.Encode(typ.OID, pgtype.TextFormatCode, value, nil)
+		if err != nil {
+			return "", err
+		}
+		if buf != nil {
+			text := string(buf)
+			texts[i] = &text
+		}
+	}
+	payload, err := json.Marshal(texts)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(pageTokenMac(signature, payload)), nil
+}
+
+func decodePageToken(token string, signature []byte, size int) ([]any, error) {
+	encoded, encodedMac, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidPageToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+	}
+	if !hmac.Equal(mac, pageTokenMac(signature, payload)) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidPageToken)
+	}
+	var texts []*string
+	if err = json.Unmarshal(payload, &texts); err != nil || len(texts) != size {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPageToken)
+	}
+	values := make([]any, len(texts))
+	for i, text := range texts {
+		if text != nil {
+			values[i] = *text
+		}
+	}
+	return values, nil
+}
+
+// QueryPage — AIP-158 page of query ordered by its ORDER BY terms with the
+// primary key as a tiebreaker. token is nextToken of the previous page, empty
+// for the first one; nextToken is empty on the last page. size is clamped to
+// 1..1000, 50 by default.
+func (t *table[F, T]) QueryPage(
+	ctx context.Context,
+	db DB,
+	query *SelectQuery[F],
+	token string,
+	size int,
+) (rows []T, nextToken string, err error) {
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	size = min(size, maxPageSize)
+
+	q := *query
+	q.limit, q.offset = 0, 0
+	q.whereClauses = slices.Clone(q.whereClauses)
+	q.orderBy = slices.Clone(q.orderBy)
+	termFields := make([]F, 0, len(q.orderBy)+len(t.primaryKey))
+	for _, term := range q.orderBy {
+		termFields = append(termFields, term.Field)
+	}
+	for _, f := range exceptFields(t.primaryKey, termFields) {
+		q.orderBy = append(q.orderBy, Asc(f))
+		termFields = append(termFields, f)
+	}
+	if len(q.orderBy) == 0 {
+		return nil, "", fmt.Errorf("pgx-orm: %s has no order terms for pagination: %w", t.alias, ErrEmptyFields)
+	}
+	// cursor values are read from scanned rows
+	if len(q.usingFields) > 0 {
+		q.usingFields = slices.Concat(q.usingFields, exceptFields(termFields, q.usingFields))
+	}
+	signature := pageSignature(&q)
+
+	var cursor []any
+	if token != "" {
+		if cursor, err = decodePageToken(token, signature, len(q.orderBy)); err != nil {
+			return nil, "", err
+		}
+	}
+	rows, err = t.Query(ctx, db, q.paginate(cursor, size+1, t.primaryKey, q.orderBy...))
+	if err != nil || len(rows) <= size {
+		return rows, "", err
+	}
+	last := rows[size-1]
+	values := make([]any, len(termFields))
+	for i, f := range termFields {
+		values[i] = last.getValue(f)()
+	}
+	nextToken, err = encodePageToken(signature, values)
+	if err != nil {
+		return nil, "", err
+	}
+	return rows[:size], nextToken, nil
+}
@@ -0,0 +1,71 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaginate(t *testing.T) {
+	sql, args := newTestSelect(testField("id")).
+		Paginate([]any{"a", 7}, 10, Desc[fieldAlias](testField("name")), Desc[fieldAlias](testField("id"))).
+		Build()
+	want := "SELECT posts.id FROM posts AS posts WHERE (posts.name, posts.id) < ($1, $2) ORDER BY posts.name DESC, posts.id DESC LIMIT 10;"
+	if sql != want || len(args) != 2 {
+		t.Fatalf("uniform keyset fail:\nwant: %s\ngot : %s", want, sql)
+	}
+	sql, _ = newTestSelect(testField("id")).
+		Paginate([]any{"a", 1, 7}, 10, Desc[fieldAlias](testField("name")), Asc[fieldAlias](testField("age")), Asc[fieldAlias](testField("id"))).
+		Build()
+	want = "SELECT posts.id FROM posts AS posts WHERE (posts.name < $1 OR posts.name = $1 AND " +
+		"(posts.age > $2 OR posts.age IS NULL OR posts.age = $2 AND (posts.id > $3 OR posts.id IS NULL))) " +
+		"ORDER BY posts.name DESC, posts.age ASC, posts.id ASC LIMIT 10;"
+	if sql != want {
+		t.Fatalf("mixed keyset fail:\nwant: %s\ngot : %s", want, sql)
+	}
+
+	pk := []fieldAlias{testField("id")}
+	for _, c := range []struct {
+		cursor []any
+		terms  []OrderTerm[fieldAlias]
+		want   string
+		args   int
+	}{
+		{[]any{7}, []OrderTerm[fieldAlias]{Asc[fieldAlias](testField("id"))}, "(posts.id) > ($1)", 1},
+		{[]any{"a", 7}, []OrderTerm[fieldAlias]{Asc[fieldAlias](testField("name")), Asc[fieldAlias](testField("id"))},
+			"(posts.name > $1 OR posts.name IS NULL OR posts.name = $1 AND posts.id > $2)", 2},
+		{[]any{nil, 7}, []OrderTerm[fieldAlias]{Asc[fieldAlias](testField("name")), Asc[fieldAlias](testField("id"))},
+			"(posts.name IS NULL AND posts.id > $1)", 1},
+		{[]any{nil, 7}, []OrderTerm[fieldAlias]{Asc[fieldAlias](testField("name")).WithNulls(NullsFirst), Asc[fieldAlias](testField("id"))},
+			"(posts.name IS NOT NULL OR posts.name IS NULL AND posts.id > $1)", 1},
+		{[]any{nil}, []OrderTerm[fieldAlias]{Desc[fieldAlias](testField("name")).WithNulls(NullsLast)}, "FALSE", 0},
+	} {
+		sql, args = newTestSelect(testField("id")).paginate(c.cursor, 10, pk, c.terms...).Build()
+		if !strings.Contains(sql, "WHERE "+c.want+" ORDER BY") || len(args) != c.args {
+			t.Fatalf("nulls keyset fail:\nwant: %s\ngot : %s %v", c.want, sql, args)
+		}
+	}
+
+	signature := pageSignature(newTestSelect(testField("id")))
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	token, err := encodePageToken(signature, []any{int64(42), created, nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := decodePageToken(token, signature, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(values); got != "[42 2024-01-02 03:04:05Z <nil>]" {
+		t.Fatalf("cursor mismatch: %s", got)
+	}
+	other := pageSignature(newTestSelect(testField("name")))
+	if _, err = decodePageToken(token, other, 3); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("token of another query must be rejected, got %v", err)
+	}
+	if _, err = decodePageToken(token[1:], signature, 3); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("tampered token must be rejected, got %v", err)
+	}
+}
@@ -3,12 +3,24 @@ package orm
 import (
 	"context"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"iter"
+	"slices"
+	"time"
 )
 
 type protoCallOptions[F fieldAlias, S targeter[F], T proto.Message] struct {
 	excludeFields  []F
 	conflictFields []F
+	noUpdateFields []F
 	copyFields     []F
+	returning      []F
+	timeout        time.Duration
+	preloads       []Preloader[F, S, T]
+	deleteOrphans  bool
+	deleted        deletedScope
+	version        any
+	hooks          map[hookPoint][]Hook[S, T]
 }
 
 func (o *protoCallOptions[F, S, T]) toScannerCallOptions() []ScannerCallOptions[F, S] {
@@ -19,9 +31,27 @@ func (o *protoCallOptions[F, S, T]) toScannerCallOptions() []ScannerCallOptions[
 	if len(o.conflictFields) > 0 {
 		ret = append(ret, WithScannerConflictFields[F, S](o.conflictFields...))
 	}
+	if len(o.noUpdateFields) > 0 {
+		ret = append(ret, WithScannerNoUpdateFields[F, S](o.noUpdateFields...))
+	}
 	if len(o.copyFields) > 0 {
 		ret = append(ret, WithScannerCopyFields[F, S](o.copyFields...))
 	}
+	if len(o.returning) > 0 {
+		ret = append(ret, WithScannerReturningFields[F, S](o.returning...))
+	}
+	if o.timeout > 0 {
+		ret = append(ret, WithScannerStatementTimeout[F, S](o.timeout))
+	}
+	if o.version != nil {
+		ret = append(ret, WithScannerExpectedVersion[F, S](o.version))
+	}
+	switch o.deleted {
+	case includeDeleted:
+		ret = append(ret, WithScannerDeleted[F, S]())
+	case onlyDeleted:
+		ret = append(ret, WithScannerOnlyDeleted[F, S]())
+	}
 	return ret
 }
 
@@ -35,6 +65,8 @@ func (f callOptionsFn[F, S, T]) apply(opts *protoCallOptions[F, S, T]) {
 	f(opts)
 }
 
+// WithExcludeFields — columns left out of every write: INSERT, UPDATE and
+// DO UPDATE SET of upserts
 func WithExcludeFields[F fieldAlias, S targeter[F], T proto.Message](fields ...F) ProtoCallOption[F, S, T] {
 	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
 		opts.excludeFields = fields
@@ -53,6 +85,69 @@ func WithCopyFields[F fieldAlias, S targeter[F], T proto.Message](fields ...F) P
 	})
 }
 
+// WithReturningFields — columns returned by *Ret methods, the rest of the
+// entity is left zero
+func WithReturningFields[F fieldAlias, S targeter[F], T proto.Message](fields ...F) ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.returning = fields
+	})
+}
+
+// WithNoUpdateFields — columns inserted by upserts but kept on conflict, e.g.
+// created_by
+func WithNoUpdateFields[F fieldAlias, S targeter[F], T proto.Message](fields ...F) ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.noUpdateFields = fields
+	})
+}
+
+// WithStatementTimeout — client-side deadline of the call, see
+// WithScannerStatementTimeout
+func WithStatementTimeout[F fieldAlias, S targeter[F], T proto.Message](timeout time.Duration) ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.timeout = timeout
+	})
+}
+
+// WithPreload loads relations of the returned entities with one query per
+// relation, e.g. ListBy(ctx, q, WithPreload(Users.Posts)). Applies to GetBy,
+// ListBy and ListPage.
+func WithPreload[F fieldAlias, S targeter[F], T proto.Message](preloads ...Preloader[F, S, T]) ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.preloads = append(opts.preloads, preloads...)
+	})
+}
+
+// WithDeleteOrphans makes SaveGraph delete children and many-to-many links
+// missing from the repeated fields of the saved entity
+func WithDeleteOrphans[F fieldAlias, S targeter[F], T proto.Message]() ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.deleteOrphans = true
+	})
+}
+
+// WithDeleted — reads include soft deleted rows
+func WithDeleted[F fieldAlias, S targeter[F], T proto.Message]() ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.deleted = includeDeleted
+	})
+}
+
+// OnlyDeleted — reads match soft deleted rows only
+func OnlyDeleted[F fieldAlias, S targeter[F], T proto.Message]() ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.deleted = onlyDeleted
+	})
+}
+
+// WithExpectedVersion — version checked by updates of versioned tables
+// instead of the version of the entity
+func WithExpectedVersion[F fieldAlias, S targeter[F], T proto.Message](version any) ProtoCallOption[F, S, T] {
+	return callOptionsFn[F, S, T](func(opts *protoCallOptions[F, S, T]) {
+		opts.version = version
+	})
+}
+
 type ProtoRepository[F fieldAlias, S targeter[F], T proto.Message] interface {
 	Table() TableI[F, S]
 	ScannerRepository() ScannerRepository[F, S]
@@ -61,14 +156,41 @@ type ProtoRepository[F fieldAlias, S targeter[F], T proto.Message] interface {
 	InsertMany(ctx context.Context, entities []T, opts ...ProtoCallOption[F, S, T]) error
 	Update(ctx context.Context, entity T, clause Clause[F], opts ...ProtoCallOption[F, S, T]) error
 	UpdateRet(ctx context.Context, entity T, clause Clause[F], opts ...ProtoCallOption[F, S, T]) (T, error)
-	//Upsert(ctx context.Context, entity T, conflictFields []F, opts ...ProtoCallOption[F, S, T]) error
-	//UpsertRet(ctx context.Context, entity T, conflictFields []F, opts ...ProtoCallOption[F, S, T]) (T, error)
-	//UpsertIgnore(ctx context.Context, entity T, opts ...ProtoCallOption[F, S, T]) error
+	UpdateMasked(ctx context.Context, entity T, mask *fieldmaskpb.FieldMask, clause Clause[F], opts ...ProtoCallOption[F, S, T]) error
+	Upsert(ctx context.Context, entity T, opts ...ProtoCallOption[F, S, T]) error
+	UpsertRet(ctx context.Context, entity T, opts ...ProtoCallOption[F, S, T]) (T, error)
+	UpsertIgnore(ctx context.Context, entity T, opts ...ProtoCallOption[F, S, T]) error
+	UpsertMany(ctx context.Context, entities []T, opts ...ProtoCallOption[F, S, T]) error
+	UpsertIgnoreMany(ctx context.Context, entities []T, opts ...ProtoCallOption[F, S, T]) error
+	SaveGraph(ctx context.Context, entity T, opts ...ProtoCallOption[F, S, T]) error
+
+	Delete(ctx context.Context, clause Clause[F], opts ...ProtoCallOption[F, S, T]) error
+	HardDelete(ctx context.Context, clause Clause[F], opts ...ProtoCallOption[F, S, T]) error
+	Restore(ctx context.Context, clause Clause[F], opts ...ProtoCallOption[F, S, T]) error
+	Exists(ctx context.Context, clause Clause[F], opts ...ProtoCallOption[F, S, T]) (bool, error)
 
 	GetBy(ctx context.Context, query ormQuery, opts ...ProtoCallOption[F, S, T]) (T, error)
 	ListBy(ctx context.Context, query ormQuery, opts ...ProtoCallOption[F, S, T]) ([]T, error)
+	Stream(ctx context.Context, query ormQuery, opts ...ProtoCallOption[F, S, T]) iter.Seq2[T, error]
+	ListPage(
+		ctx context.Context,
+		query *SelectQuery[F],
+		token string,
+		size int,
+		opts ...ProtoCallOption[F, S, T],
+	) ([]T, string, error)
 	Exec(ctx context.Context, query ormQuery, opts ...ProtoCallOption[F, S, T]) error
 	ExecAffected(ctx context.Context, query ormQuery, opts ...ProtoCallOption[F, S, T]) (int64, error)
+
+	BatchInsert(b *Batch, entity T, opts ...ProtoCallOption[F, S, T]) *BatchResult[int64]
+	BatchInsertRet(b *Batch, entity T, opts ...ProtoCallOption[F, S, T]) *BatchResult[T]
+	BatchUpdate(b *Batch, entity T, clause Clause[F], opts ...ProtoCallOption[F, S, T]) *BatchResult[int64]
+	BatchUpdateRet(b *Batch, entity T, clause Clause[F], opts ...ProtoCallOption[F, S, T]) *BatchResult[T]
+	BatchUpsert(b *Batch, entity T, opts ...ProtoCallOption[F, S, T]) *BatchResult[int64]
+	BatchDelete(b *Batch, clause Clause[F]) *BatchResult[int64]
+	BatchGetBy(b *Batch, query ormQuery, opts ...ProtoCallOption[F, S, T]) *BatchResult[T]
+	BatchListBy(b *Batch, query ormQuery, opts ...ProtoCallOption[F, S, T]) *BatchResult[[]T]
+	BatchExec(b *Batch, query ormQuery) *BatchResult[int64]
 }
 
 type genericRepository[F fieldAlias, S targeter[F], T proto.Message] struct {
@@ -100,10 +222,7 @@ func (g *genericRepository[F, S, T]) ScannerRepository() ScannerRepository[F, S]
 }
 
 func (g *genericRepository[F, S, T]) opts(opts []ProtoCallOption[F, S, T]) *protoCallOptions[F, S, T] {
-	op := &protoCallOptions[F, S, T]{
-		excludeFields: g.scannerRepo.table.allFields,
-		copyFields:    g.scannerRepo.table.allFields,
-	}
+	op := &protoCallOptions[F, S, T]{}
 	for _, o := range g.defaultOpts {
 		o.apply(op)
 	}
@@ -117,29 +236,49 @@ func (g *genericRepository[F, S, T]) Insert(
 	entity T,
 	opts ...ProtoCallOption[F, S, T],
 ) error {
-	return g.scannerRepo.Insert(ctx, g.downcast(entity), g.opts(opts).toScannerCallOptions()...)
+	opt := g.opts(opts)
+	model, err := g.beforeWrite(ctx, opt, hookBeforeInsert, entity)
+	if err != nil {
+		return err
+	}
+	if err = g.scannerRepo.Insert(ctx, model, opt.toScannerCallOptions()...); err != nil {
+		return err
+	}
+	if g.scannerRepo.table.createdAt != nil {
+		g.scannerRepo.table.copyTimestamps(entity.ProtoReflect(), g.upcast(model).ProtoReflect())
+	}
+	return opt.runHook(ctx, hookAfterInsert, entity, model)
 }
 func (g *genericRepository[F, S, T]) InsertRet(
 	ctx context.Context,
 	entity T,
 	opts ...ProtoCallOption[F, S, T],
 ) (ret T, err error) {
-	model, err := g.scannerRepo.InsertRet(ctx, g.downcast(entity), g.opts(opts).toScannerCallOptions()...)
+	opt := g.opts(opts)
+	model, err := g.beforeWrite(ctx, opt, hookBeforeInsert, entity)
 	if err != nil {
 		return ret, err
 	}
-	return g.upcast(model), err
+	if model, err = g.scannerRepo.InsertRet(ctx, model, opt.toScannerCallOptions()...); err != nil {
+		return ret, err
+	}
+	ret = g.upcast(model)
+	return ret, opt.runHook(ctx, hookAfterInsert, ret, model)
 }
 func (g *genericRepository[F, S, T]) InsertMany(
 	ctx context.Context,
 	entities []T,
 	opts ...ProtoCallOption[F, S, T],
 ) error {
-	models := make([]S, 0)
-	for _, e := range entities {
-		models = append(models, g.downcast(e))
+	opt := g.opts(opts)
+	models, err := g.beforeWriteAll(ctx, opt, hookBeforeInsert, entities)
+	if err != nil {
+		return err
+	}
+	if err = g.scannerRepo.InsertMany(ctx, models, opt.toScannerCallOptions()...); err != nil {
+		return err
 	}
-	return g.scannerRepo.InsertMany(ctx, models, g.opts(opts).toScannerCallOptions()...)
+	return opt.runHooks(ctx, hookAfterInsert, entities, models)
 }
 
 func (g *genericRepository[F, S, T]) Update(
@@ -148,7 +287,28 @@ func (g *genericRepository[F, S, T]) Update(
 	clause Clause[F],
 	opts ...ProtoCallOption[F, S, T],
 ) error {
-	return g.scannerRepo.Update(ctx, g.downcast(entity), clause, g.opts(opts).toScannerCallOptions()...)
+	return g.update(ctx, entity, clause, g.opts(opts))
+}
+
+// update runs the update hooks around scannerRepo.Update
+func (g *genericRepository[F, S, T]) update(
+	ctx context.Context,
+	entity T,
+	clause Clause[F],
+	opt *protoCallOptions[F, S, T],
+) error {
+	model, err := g.beforeWrite(ctx, opt, hookBeforeUpdate, entity)
+	if err != nil {
+		return err
+	}
+	if err = g.scannerRepo.Update(ctx, model, clause, opt.toScannerCallOptions()...); err != nil {
+		return err
+	}
+	g.scannerRepo.table.advanceVersion(entity.ProtoReflect(), model, opt.version)
+	if g.scannerRepo.table.createdAt != nil {
+		g.scannerRepo.table.copyTimestamps(entity.ProtoReflect(), g.upcast(model).ProtoReflect())
+	}
+	return opt.runHook(ctx, hookAfterUpdate, entity, model)
 }
 
 func (g *genericRepository[F, S, T]) UpdateRet(
@@ -157,45 +317,243 @@ func (g *genericRepository[F, S, T]) UpdateRet(
 	clause Clause[F],
 	opts ...ProtoCallOption[F, S, T],
 ) (ret T, err error) {
-	model, err := g.scannerRepo.UpdateRet(ctx, g.downcast(entity), clause, g.opts(opts).toScannerCallOptions()...)
+	opt := g.opts(opts)
+	model, err := g.beforeWrite(ctx, opt, hookBeforeUpdate, entity)
+	if err != nil {
+		return ret, err
+	}
+	if model, err = g.scannerRepo.UpdateRet(ctx, model, clause, opt.toScannerCallOptions()...); err != nil {
+		return ret, err
+	}
+	ret = g.upcast(model)
+	return ret, opt.runHook(ctx, hookAfterUpdate, ret, model)
+}
+
+// UpdateMasked — update only columns of mask paths, an unknown path fails
+// with ErrInvalidFieldMask before hitting the database
+func (g *genericRepository[F, S, T]) UpdateMasked(
+	ctx context.Context,
+	entity T,
+	mask *fieldmaskpb.FieldMask,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	fields, err := g.scannerRepo.table.maskFields(mask)
+	if err != nil {
+		return err
+	}
+	opt := g.opts(opts)
+	opt.excludeFields = slices.Concat(opt.excludeFields, exceptFields(g.scannerRepo.table.allFields, fields))
+	return g.update(ctx, entity, clause, opt)
+}
+
+func (g *genericRepository[F, S, T]) Upsert(
+	ctx context.Context,
+	entity T,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.UpsertMany(ctx, []T{entity}, opts...)
+}
+func (g *genericRepository[F, S, T]) UpsertRet(
+	ctx context.Context,
+	entity T,
+	opts ...ProtoCallOption[F, S, T],
+) (ret T, err error) {
+	opt := g.opts(opts)
+	model, err := g.beforeWrite(ctx, opt, hookBeforeInsert, entity)
 	if err != nil {
 		return ret, err
 	}
-	return g.upcast(model), err
-}
-
-// func (g *genericRepository[F, S, T]) Upsert(
-//
-//	ctx context.Context,
-//	entity T,
-//	conflictFields []F,
-//	opts ...ProtoCallOption[F, S, T],
-//
-//	) error {
-//		return g.scannerRepo.Upsert(ctx, g.downcast(entity), conflictFields, g.opts(opts).toScannerCallOptions()...)
-//	}
-//
-// func (g *genericRepository[F, S, T]) UpsertRet(
-//
-//	ctx context.Context,
-//	entity T,
-//	conflictFields []F,
-//	opts ...ProtoCallOption[F, S, T],
-//
-//	) (T, error) {
-//		model, err := g.scannerRepo.UpsertRet(ctx, g.downcast(entity), conflictFields, g.opts(opts).toScannerCallOptions()...)
-//		return g.upcast(model), err
-//	}
-//
-// func (g *genericRepository[F, S, T]) UpsertIgnore(
-//
-//	ctx context.Context,
-//	entity T,
-//	opts ...ProtoCallOption[F, S, T],
-//
-//	) error {
-//		return g.scannerRepo.UpsertIgnore(ctx, g.downcast(entity), g.opts(opts).toScannerCallOptions()...)
-//	}
+	if model, err = g.scannerRepo.UpsertRet(ctx, model, opt.toScannerCallOptions()...); err != nil {
+		return ret, err
+	}
+	ret = g.upcast(model)
+	return ret, opt.runHook(ctx, hookAfterInsert, ret, model)
+}
+func (g *genericRepository[F, S, T]) UpsertIgnore(
+	ctx context.Context,
+	entity T,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.UpsertIgnoreMany(ctx, []T{entity}, opts...)
+}
+func (g *genericRepository[F, S, T]) UpsertMany(
+	ctx context.Context,
+	entities []T,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.upsertMany(ctx, entities, g.opts(opts), g.scannerRepo.UpsertMany)
+}
+func (g *genericRepository[F, S, T]) UpsertIgnoreMany(
+	ctx context.Context,
+	entities []T,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.upsertMany(ctx, entities, g.opts(opts), g.scannerRepo.UpsertIgnoreMany)
+}
+
+// upsertMany runs the insert hooks around upsert
+func (g *genericRepository[F, S, T]) upsertMany(
+	ctx context.Context,
+	entities []T,
+	opt *protoCallOptions[F, S, T],
+	upsert func(ctx context.Context, entities []S, opts ...ScannerCallOptions[F, S]) error,
+) error {
+	models, err := g.beforeWriteAll(ctx, opt, hookBeforeInsert, entities)
+	if err != nil {
+		return err
+	}
+	if err = upsert(ctx, models, opt.toScannerCallOptions()...); err != nil {
+		return err
+	}
+	return opt.runHooks(ctx, hookAfterInsert, entities, models)
+}
+
+// SaveGraph saves entity with its relations in one transaction: the row is
+// inserted when the primary key is zero and upserted otherwise, children of
+// one-to-many fields are saved with the parent key and many-to-many targets
+// are linked. The graph is saved from a copy of entity, generated keys and
+// defaults are copied back to the messages only after the transaction (or the
+// savepoint of an outer one) is committed.
+func (g *genericRepository[F, S, T]) SaveGraph(
+	ctx context.Context,
+	entity T,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	opt := g.opts(opts)
+	scannerOpt := g.scannerRepo.opts(opt.toScannerCallOptions()...)
+	ctx, cancel := g.scannerRepo.withTimeout(ctx, scannerOpt)
+	defer cancel()
+	var graph T
+	err := runInTx(ctx, g.scannerRepo.dbGetter, func(ctx context.Context, db DB) error {
+		t := g.scannerRepo.table
+		graph = proto.Clone(entity).(T)
+		model, err := g.beforeWrite(ctx, opt, hookBeforeInsert, graph)
+		if err != nil {
+			return err
+		}
+		setters := GetFieldsSetters(model, g.scannerRepo.writeFields(scannerOpt)...)
+		saved, err := saveRow(ctx, db, t, setters, g.scannerRepo.conflictTarget(scannerOpt))
+		if err != nil {
+			return err
+		}
+		copyMessage(graph.ProtoReflect(), g.upcast(saved).ProtoReflect())
+		if err = opt.runHook(ctx, hookAfterInsert, graph, saved); err != nil {
+			return err
+		}
+		return saveGraphRelations(ctx, db, t.graph, rowColumns(t, saved), graph.ProtoReflect(), opt.deleteOrphans)
+	})
+	if err != nil {
+		return err
+	}
+	copyGraph(entity.ProtoReflect(), graph.ProtoReflect())
+	return nil
+}
+
+// beforeWrite runs the before hooks of point on entity and downcasts it after
+// them, so values the hooks set on entity are written
+func (g *genericRepository[F, S, T]) beforeWrite(
+	ctx context.Context,
+	opt *protoCallOptions[F, S, T],
+	point hookPoint,
+	entity T,
+) (S, error) {
+	models, err := g.beforeWriteAll(ctx, opt, point, []T{entity})
+	if err != nil {
+		var model S
+		return model, err
+	}
+	return models[0], nil
+}
+
+// beforeWriteAll — beforeWrite of entities, hooks get scanners of the entities
+// as they were before the hooks
+func (g *genericRepository[F, S, T]) beforeWriteAll(
+	ctx context.Context,
+	opt *protoCallOptions[F, S, T],
+	point hookPoint,
+	entities []T,
+) ([]S, error) {
+	models := g.downcastAll(entities)
+	if !opt.hasHooks(point) {
+		return models, nil
+	}
+	if err := opt.runHooks(ctx, point, entities, models); err != nil {
+		return nil, err
+	}
+	return g.downcastAll(entities), nil
+}
+func (g *genericRepository[F, S, T]) downcastAll(entities []T) []S {
+	models := make([]S, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, g.downcast(e))
+	}
+	return models
+}
+func (g *genericRepository[F, S, T]) Delete(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	opt := g.opts(opts)
+	if opt.hasHooks(hookBeforeDelete, hookAfterDelete) {
+		return g.deleteHooked(ctx, clause, opt, excludeDeleted, g.scannerRepo.table.deleteQuery(clause))
+	}
+	return g.scannerRepo.Delete(ctx, clause, opt.toScannerCallOptions()...)
+}
+func (g *genericRepository[F, S, T]) HardDelete(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	opt := g.opts(opts)
+	if opt.hasHooks(hookBeforeDelete, hookAfterDelete) {
+		return g.deleteHooked(ctx, clause, opt, includeDeleted, g.scannerRepo.table.Delete().Where(clause))
+	}
+	return g.scannerRepo.HardDelete(ctx, clause, opt.toScannerCallOptions()...)
+}
+
+// deleteHooked loads rows of clause for the delete hooks and deletes them in
+// one transaction
+func (g *genericRepository[F, S, T]) deleteHooked(
+	ctx context.Context,
+	clause Clause[F],
+	opt *protoCallOptions[F, S, T],
+	scope deletedScope,
+	query ormQuery,
+) error {
+	t := g.scannerRepo.table
+	ctx, cancel := g.scannerRepo.withTimeout(ctx, &scannerCallOptions[F, S]{timeout: opt.timeout})
+	defer cancel()
+	return runInTx(ctx, g.scannerRepo.dbGetter, func(ctx context.Context, db DB) error {
+		models, err := t.Query(ctx, db, scoped[F](t.SelectAll().Where(clause), scope))
+		if err != nil {
+			return err
+		}
+		entities := g.upcastAll(models)
+		if err = opt.runHooks(ctx, hookBeforeDelete, entities, models); err != nil {
+			return err
+		}
+		if _, err = t.Execute(ctx, db, query); err != nil {
+			return err
+		}
+		return opt.runHooks(ctx, hookAfterDelete, entities, models)
+	})
+}
+func (g *genericRepository[F, S, T]) Restore(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.scannerRepo.Restore(ctx, clause, g.opts(opts).toScannerCallOptions()...)
+}
+func (g *genericRepository[F, S, T]) Exists(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) (bool, error) {
+	return g.scannerRepo.Exists(ctx, clause, g.opts(opts).toScannerCallOptions()...)
+}
 func (g *genericRepository[F, S, T]) GetBy(
 	ctx context.Context,
 	query ormQuery,
@@ -206,7 +564,14 @@ func (g *genericRepository[F, S, T]) GetBy(
 	if err != nil {
 		return ret, err
 	}
-	return g.upcast(model), nil
+	entity := g.upcast(model)
+	if err = g.preload(ctx, opt, []S{model}, []T{entity}); err != nil {
+		return ret, err
+	}
+	if err = opt.runHook(ctx, hookAfterLoad, entity, model); err != nil {
+		return ret, err
+	}
+	return entity, nil
 }
 func (g *genericRepository[F, S, T]) ListBy(
 	ctx context.Context,
@@ -218,12 +583,77 @@ func (g *genericRepository[F, S, T]) ListBy(
 	if err != nil {
 		return nil, err
 	}
-	rets := make([]T, 0, len(models))
-	for _, model := range models {
-		entity := g.upcast(model)
-		rets = append(rets, entity)
+	entities := g.upcastAll(models)
+	if err = g.preload(ctx, opt, models, entities); err != nil {
+		return nil, err
+	}
+	if err = opt.runHooks(ctx, hookAfterLoad, entities, models); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// ListPage returns items and next_page_token of an AIP-158 list response
+func (g *genericRepository[F, S, T]) ListPage(
+	ctx context.Context,
+	query *SelectQuery[F],
+	token string,
+	size int,
+	opts ...ProtoCallOption[F, S, T],
+) ([]T, string, error) {
+	opt := g.opts(opts)
+	models, nextToken, err := g.scannerRepo.ListPage(ctx, query, token, size, opt.toScannerCallOptions()...)
+	if err != nil {
+		return nil, "", err
+	}
+	entities := g.upcastAll(models)
+	if err = g.preload(ctx, opt, models, entities); err != nil {
+		return nil, "", err
+	}
+	if err = opt.runHooks(ctx, hookAfterLoad, entities, models); err != nil {
+		return nil, "", err
+	}
+	return entities, nextToken, nil
+}
+
+func (g *genericRepository[F, S, T]) preload(ctx context.Context, opt *protoCallOptions[F, S, T], models []S, entities []T) error {
+	if len(opt.preloads) == 0 {
+		return nil
+	}
+	ctx, cancel := g.scannerRepo.withTimeout(ctx, &scannerCallOptions[F, S]{timeout: opt.timeout})
+	defer cancel()
+	db := g.scannerRepo.dbGetter(ctx, SqlQuery)
+	for _, p := range opt.preloads {
+		if err := p.preload(ctx, db, models, entities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stream upcasts rows one by one, see ScannerRepository.Stream
+func (g *genericRepository[F, S, T]) Stream(
+	ctx context.Context,
+	query ormQuery,
+	opts ...ProtoCallOption[F, S, T],
+) iter.Seq2[T, error] {
+	opt := g.opts(opts)
+	models := g.scannerRepo.Stream(ctx, query, opt.toScannerCallOptions()...)
+	return func(yield func(T, error) bool) {
+		for model, err := range models {
+			var entity T
+			if err == nil {
+				entity = g.upcast(model)
+				if err = opt.runHook(ctx, hookAfterLoad, entity, model); err != nil {
+					yield(entity, err)
+					return
+				}
+			}
+			if !yield(entity, err) {
+				return
+			}
+		}
 	}
-	return rets, nil
 }
 func (g *genericRepository[F, S, T]) Exec(
 	ctx context.Context,
@@ -240,3 +670,130 @@ func (g *genericRepository[F, S, T]) ExecAffected(
 ) (int64, error) {
 	return g.scannerRepo.ExecAffected(ctx, query, g.opts(opts).toScannerCallOptions()...)
 }
+
+// ---------------------------------------------------------------------------
+// batch variants, results are upcasted lazily on BatchResult.Result ----------
+// ---------------------------------------------------------------------------
+
+func (g *genericRepository[F, S, T]) upcastAll(models []S) []T {
+	rets := make([]T, 0, len(models))
+	for _, model := range models {
+		rets = append(rets, g.upcast(model))
+	}
+	return rets
+}
+func (g *genericRepository[F, S, T]) BatchInsert(b *Batch, entity T, opts ...ProtoCallOption[F, S, T]) *BatchResult[int64] {
+	return g.scannerRepo.BatchInsert(b, g.downcast(entity), g.opts(opts).toScannerCallOptions()...)
+}
+func (g *genericRepository[F, S, T]) BatchInsertRet(b *Batch, entity T, opts ...ProtoCallOption[F, S, T]) *BatchResult[T] {
+	return mapBatchResult(g.scannerRepo.BatchInsertRet(b, g.downcast(entity), g.opts(opts).toScannerCallOptions()...), g.upcast)
+}
+func (g *genericRepository[F, S, T]) BatchUpdate(
+	b *Batch,
+	entity T,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) *BatchResult[int64] {
+	return g.scannerRepo.BatchUpdate(b, g.downcast(entity), clause, g.opts(opts).toScannerCallOptions()...)
+}
+func (g *genericRepository[F, S, T]) BatchUpdateRet(
+	b *Batch,
+	entity T,
+	clause Clause[F],
+	opts ...ProtoCallOption[F, S, T],
+) *BatchResult[T] {
+	return mapBatchResult(g.scannerRepo.BatchUpdateRet(b, g.downcast(entity), clause, g.opts(opts).toScannerCallOptions()...), g.upcast)
+}
+func (g *genericRepository[F, S, T]) BatchUpsert(b *Batch, entity T, opts ...ProtoCallOption[F, S, T]) *BatchResult[int64] {
+	return g.scannerRepo.BatchUpsert(b, g.downcast(entity), g.opts(opts).toScannerCallOptions()...)
+}
+func (g *genericRepository[F, S, T]) BatchDelete(b *Batch, clause Clause[F]) *BatchResult[int64] {
+	return g.scannerRepo.BatchDelete(b, clause)
+}
+func (g *genericRepository[F, S, T]) BatchGetBy(b *Batch, query ormQuery, opts ...ProtoCallOption[F, S, T]) *BatchResult[T] {
+	return mapBatchResult(g.scannerRepo.BatchGetBy(b, query, g.opts(opts).toScannerCallOptions()...), g.upcast)
+}
+func (g *genericRepository[F, S, T]) BatchListBy(b *Batch, query ormQuery, opts ...ProtoCallOption[F, S, T]) *BatchResult[[]T] {
+	return mapBatchResult(g.scannerRepo.BatchListBy(b, query, g.opts(opts).toScannerCallOptions()...), g.upcastAll)
+}
+func (g *genericRepository[F, S, T]) BatchExec(b *Batch, query ormQuery) *BatchResult[int64] {
+	return g.scannerRepo.BatchExec(b, query)
+}
+
+// ---------------------------------------------------------------------------
+// key helpers used by generated GetByPK, GetByEmail, ... --------------------
+// ---------------------------------------------------------------------------
+
+func keyClause[F fieldAlias](fields []F, values []any) Clause[F] {
+	clauses := make([]Clause[F], 0, len(fields))
+	for i, f := range fields {
+		clauses = append(clauses, &FieldClause[F]{Field: f, Operator: "=", Right: &ParamExprClause[F]{Value: values[i]}})
+	}
+	if len(clauses) == 1 {
+		return clauses[0]
+	}
+	return &AndClause[F]{Clauses: clauses}
+}
+
+func (g *genericRepository[F, S, T]) getByKey(
+	ctx context.Context,
+	fields []F,
+	values []any,
+	opts ...ProtoCallOption[F, S, T],
+) (T, error) {
+	table := g.scannerRepo.table
+	return g.GetBy(ctx, table.SelectAll().Where(keyClause(fields, values)), opts...)
+}
+
+// listByKeys — WHERE key = ANY($1), values is a slice of the key type
+func (g *genericRepository[F, S, T]) listByKeys(
+	ctx context.Context,
+	field F,
+	values any,
+	opts ...ProtoCallOption[F, S, T],
+) ([]T, error) {
+	table := g.scannerRepo.table
+	clause := &FieldClause[F]{Field: field, Operator: "= ANY", Right: &SliceExprClause[F]{Values: values}}
+	return g.ListBy(ctx, table.SelectAll().Where(clause), opts...)
+}
+
+func (g *genericRepository[F, S, T]) deleteByKey(
+	ctx context.Context,
+	fields []F,
+	values []any,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.Delete(ctx, keyClause(fields, values), opts...)
+}
+
+func (g *genericRepository[F, S, T]) restoreByKey(
+	ctx context.Context,
+	fields []F,
+	values []any,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	return g.Restore(ctx, keyClause(fields, values), opts...)
+}
+
+func (g *genericRepository[F, S, T]) existsByKey(
+	ctx context.Context,
+	fields []F,
+	values []any,
+	opts ...ProtoCallOption[F, S, T],
+) (bool, error) {
+	return g.Exists(ctx, keyClause(fields, values), opts...)
+}
+
+// updateByKey — key values are taken from the entity, key columns are not set
+func (g *genericRepository[F, S, T]) updateByKey(
+	ctx context.Context,
+	entity T,
+	fields []F,
+	opts ...ProtoCallOption[F, S, T],
+) error {
+	model := g.downcast(entity)
+	opt := g.opts(opts)
+	opt.excludeFields = slices.Concat(opt.excludeFields, fields)
+	clause := keyClause(fields, GetFieldsValues(model, fields...))
+	return g.update(ctx, entity, clause, opt)
+}
@@ -0,0 +1,38 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/types/known/structpb"
+	"strings"
+	"testing"
+)
+
+func TestKeyHelpers(t *testing.T) {
+	tb := newTestTable("id", "type").withPrimaryKey(testField("id"))
+	db := &testQueryDB{rows: &testRows{data: [][]any{{int64(1), "a"}, {int64(2), "b"}}}}
+	repo := newGenericRepository(
+		newGenericScannerRepository(tb, NewDbGetter(db)),
+		func(v *structpb.Value) testScanner { return testScanner{} },
+		func(s testScanner) *structpb.Value {
+			return structpb.NewStringValue(fmt.Sprint(s.getValue(testField("type"))()))
+		},
+	)
+
+	got, err := repo.listByKeys(context.Background(), testField("id"), []int64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].GetStringValue() != "b" {
+		t.Fatalf("batch lookup result mismatch: %v", got)
+	}
+	want := "SELECT users.id, users.type FROM users AS users WHERE users.id = ANY ($1);"
+	if db.sql != want || len(db.args) != 1 || fmt.Sprint(db.args[0]) != "[1 2]" {
+		t.Fatalf("batch lookup mismatch:\nwant: %s\ngot : %s %v", want, db.sql, db.args)
+	}
+
+	sql, args := tb.SelectAll().Where(keyClause([]fieldAlias{testField("id"), testField("type")}, []any{1, "a"})).Build()
+	if want = "WHERE (users.id = $1 AND users.type = $2)"; !strings.Contains(sql, want) || len(args) != 2 {
+		t.Fatalf("composite key mismatch:\nwant: %s\ngot : %s", want, sql)
+	}
+}
@@ -0,0 +1,146 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"reflect"
+	"slices"
+)
+
+// ---------------------------------------------------------------------------
+// Eager loading --------------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// Preloader loads related rows of parents and attaches them to entities, see
+// WithPreload
+type Preloader[F fieldAlias, S targeter[F], T proto.Message] interface {
+	preload(ctx context.Context, db DB, parents []S, entities []T) error
+}
+
+// Relation — one-to-many relation from a parent table (P) to a child table
+// (C) which rows reference the parent key. Children are attached to the
+// repeated field of the parent message.
+type Relation[
+	PF fieldAlias, PS targeter[PF], PT proto.Message,
+	CF fieldAlias, CS targeter[CF], CT proto.Message,
+] struct {
+	table     *table[CF, CS]
+	field     protoreflect.Name
+	parentKey PF
+	childKey  CF
+	nested    []Preloader[CF, CS, CT]
+	upcast    TypeCaster[CS, CT]
+	downcast  TypeCaster[CT, CS]
+}
+
+func newRelation[
+	PF fieldAlias, PS targeter[PF], PT proto.Message,
+	CF fieldAlias, CS targeter[CF], CT proto.Message,
+](
+	table *table[CF, CS],
+	field protoreflect.Name,
+	parentKey PF,
+	childKey CF,
+	upcast TypeCaster[CS, CT],
+	downcast TypeCaster[CT, CS],
+) *Relation[PF, PS, PT, CF, CS, CT] {
+	return &Relation[PF, PS, PT, CF, CS, CT]{
+		table: table, field: field, parentKey: parentKey, childKey: childKey, upcast: upcast, downcast: downcast,
+	}
+}
+
+// With preloads relations of the children too, e.g. Users.Posts.With(Posts.Comments)
+func (r *Relation[PF, PS, PT, CF, CS, CT]) With(nested ...Preloader[CF, CS, CT]) *Relation[PF, PS, PT, CF, CS, CT] {
+	ret := *r
+	ret.nested = slices.Concat(r.nested, nested)
+	return &ret
+}
+
+// WithCasters sets casters of the children. Generated relations have them
+// unless the child table needs user casters, e.g.
+// Users.Posts.WithCasters(ScannerToPost(upcastUlid), PostToScanner(downcastUlid))
+func (r *Relation[PF, PS, PT, CF, CS, CT]) WithCasters(
+	upcast TypeCaster[CS, CT],
+	downcast TypeCaster[CT, CS],
+) *Relation[PF, PS, PT, CF, CS, CT] {
+	ret := *r
+	ret.upcast, ret.downcast = upcast, downcast
+	return &ret
+}
+
+// preload selects children of all parents with one "= ANY($1)" query
+func (r *Relation[PF, PS, PT, CF, CS, CT]) preload(ctx context.Context, db DB, parents []PS, entities []PT) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	if r.upcast == nil {
+		return fmt.Errorf("pgx-orm: preload %s: casters of %s are not set, see WithCasters", r.field, r.table.alias)
+	}
+	fd := entities[0].ProtoReflect().Descriptor().Fields().ByName(r.field)
+	if fd == nil || !fd.IsList() {
+		return fmt.Errorf("pgx-orm: preload %s: no repeated field in %s", r.field, entities[0].ProtoReflect().Descriptor().FullName())
+	}
+
+	keys := make([]any, 0, len(parents))
+	byKey := make(map[any][]int, len(parents))
+	for i, parent := range parents {
+		key := relationKey(parent.getValue(r.parentKey)())
+		if key == nil {
+			continue
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], i)
+		entities[i].ProtoReflect().Clear(fd)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	children, err := r.table.Query(ctx, db, r.table.SelectAll().Where(&FieldClause[CF]{
+		Field: r.childKey, Operator: "= ANY", Right: &SliceExprClause[CF]{Values: typedSlice(keys)},
+	}))
+	if err != nil {
+		return err
+	}
+	childEntities := make([]CT, len(children))
+	for i, child := range children {
+		childEntities[i] = r.upcast(child)
+	}
+	for _, nested := range r.nested {
+		if err = nested.preload(ctx, db, children, childEntities); err != nil {
+			return err
+		}
+	}
+	for i, child := range children {
+		for _, parent := range byKey[relationKey(child.getValue(r.childKey)())] {
+			list := entities[parent].ProtoReflect().Mutable(fd).List()
+			list.Append(protoreflect.ValueOfMessage(childEntities[i].ProtoReflect()))
+		}
+	}
+	return nil
+}
+
+// relationKey dereferences keys of nullable columns, so they match the
+// parent key values
+func relationKey(value any) any {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Pointer {
+		return value
+	}
+	if rv.IsNil() {
+		return nil
+	}
+	return rv.Elem().Interface()
+}
+
+// typedSlice converts keys to a slice of their type for the array parameter
+func typedSlice(values []any) any {
+	ret := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(values[0])), 0, len(values))
+	for _, v := range values {
+		ret = reflect.Append(ret, reflect.ValueOf(v))
+	}
+	return ret.Interface()
+}
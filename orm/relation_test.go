@@ -0,0 +1,39 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/types/known/structpb"
+	"testing"
+)
+
+func TestRelationPreload(t *testing.T) {
+	children := newTestTable("id", "parent_id")
+	upcast := func(s testScanner) *structpb.Value {
+		return structpb.NewNumberValue(float64(s.getValue(testField("id"))().(int)))
+	}
+	rel := newRelation[fieldAlias, testScanner, *structpb.ListValue, fieldAlias, testScanner, *structpb.Value](
+		children, "values", testField("id"), testField("parent_id"), nil, nil,
+	)
+
+	parents := []testScanner{{"id": 1}, {"id": 2}, {"id": 3}}
+	entities := []*structpb.ListValue{{}, {}, {}}
+	db := &testQueryDB{rows: &testRows{data: [][]any{{10, 1}, {11, 2}, {12, 1}}}}
+	if err := rel.preload(context.Background(), db, parents, entities); err == nil {
+		t.Fatal("preload without casters must fail")
+	}
+	if err := rel.WithCasters(upcast, nil).preload(context.Background(), db, parents, entities); err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT users.id, users.parent_id FROM users AS users WHERE users.parent_id = ANY ($1);"
+	if db.sql != want || fmt.Sprint(db.args) != "[[1 2 3]]" {
+		t.Fatalf("preload query fail:\nwant: %s\ngot : %s %v", want, db.sql, db.args)
+	}
+	got := make([]string, len(entities))
+	for i, e := range entities {
+		got[i] = fmt.Sprint(e.AsSlice())
+	}
+	if fmt.Sprint(got) != "[[10 12] [11] []]" {
+		t.Fatalf("children attach fail: %v", got)
+	}
+}
@@ -0,0 +1,156 @@
+package orm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Read/write splitting -------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+const (
+	defaultStickyWindow   = 5 * time.Second
+	defaultHealthInterval = 5 * time.Second
+)
+
+// Pinger is implemented by *pgxpool.Pool and *pgx.Conn, replicas without it
+// are always considered healthy
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type replica struct {
+	db      DB
+	healthy atomic.Bool
+}
+
+// ReplicaRouter routes SqlQuery to replicas round-robin and SqlMutation to
+// the primary. Reads go to the primary inside RunInTx, with WithPrimary, within
+// the sticky window after a write in a WithReadYourWrites context, and when no
+// replica is healthy.
+type ReplicaRouter struct {
+	primary        DB
+	replicas       []*replica
+	next           atomic.Uint64
+	stickyWindow   time.Duration
+	healthInterval time.Duration
+}
+
+type ReplicaRouterOption func(*ReplicaRouter)
+
+// WithStickyWindow — how long reads stay on the primary after a write
+func WithStickyWindow(window time.Duration) ReplicaRouterOption {
+	return func(r *ReplicaRouter) {
+		r.stickyWindow = window
+	}
+}
+
+// WithHealthInterval — period of replica pings in ReplicaRouter.Start
+func WithHealthInterval(interval time.Duration) ReplicaRouterOption {
+	return func(r *ReplicaRouter) {
+		r.healthInterval = interval
+	}
+}
+
+func NewReplicaRouter(primary DB, replicas []DB, opts ...ReplicaRouterOption) *ReplicaRouter {
+	r := &ReplicaRouter{
+		primary:        primary,
+		stickyWindow:   defaultStickyWindow,
+		healthInterval: defaultHealthInterval,
+	}
+	for _, db := range replicas {
+		rep := &replica{db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// DbGetter to pass into generated New<Table>Repository
+func (r *ReplicaRouter) DbGetter() DbGetter {
+	return r.Get
+}
+
+func (r *ReplicaRouter) Get(ctx context.Context, operation SqlOpType) DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	session, _ := ctx.Value(sessionKey{}).(*routerSession)
+	if operation != SqlQuery {
+		// stamped on hand-out, see WithReadYourWrites
+		if session != nil {
+			session.lastWrite.Store(time.Now().UnixNano())
+		}
+		return r.primary
+	}
+	if forced, _ := ctx.Value(primaryKey{}).(bool); forced {
+		return r.primary
+	}
+	if session != nil && time.Since(time.Unix(0, session.lastWrite.Load())) < r.stickyWindow {
+		return r.primary
+	}
+	for range r.replicas {
+		rep := r.replicas[r.next.Add(1)%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return r.primary
+}
+
+// CheckHealth pings every replica once
+func (r *ReplicaRouter) CheckHealth(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pinger, ok := rep.db.(Pinger)
+		if !ok {
+			continue
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, r.healthInterval)
+		rep.healthy.Store(pinger.Ping(pingCtx) == nil)
+		cancel()
+	}
+}
+
+// Start checks replicas health every interval until ctx is done
+func (r *ReplicaRouter) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.healthInterval)
+	defer ticker.Stop()
+	for {
+		r.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type primaryKey struct{}
+
+// WithPrimary forces reads of ctx to the primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+type sessionKey struct{}
+
+type routerSession struct {
+	lastWrite atomic.Int64
+}
+
+// WithReadYourWrites tracks writes made with ctx (e.g. a request context), so
+// reads right after them see the data on the primary. A write is recorded when
+// the router hands out the primary for SqlMutation, before the statement runs:
+// the sticky window starts even if the statement fails or is never executed,
+// which only sends a few more reads to the primary.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(sessionKey{}).(*routerSession); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionKey{}, &routerSession{})
+}
@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testRouterDB struct {
+	DB
+	name    string
+	pingErr error
+}
+
+func (d *testRouterDB) Ping(context.Context) error { return d.pingErr }
+
+func TestReplicaRouter(t *testing.T) {
+	primary := &testRouterDB{name: "primary"}
+	r1 := &testRouterDB{name: "r1"}
+	r2 := &testRouterDB{name: "r2", pingErr: errors.New("down")}
+	router := NewReplicaRouter(primary, []DB{r1, r2})
+	ctx := context.Background()
+
+	if router.Get(ctx, SqlMutation) != primary {
+		t.Fatal("mutation must go to primary")
+	}
+	router.CheckHealth(ctx)
+	for i := 0; i < 4; i++ {
+		if db := router.Get(ctx, SqlQuery); db != r1 {
+			t.Fatalf("read must skip unhealthy replica, got %s", db.(*testRouterDB).name)
+		}
+	}
+	if router.Get(WithPrimary(ctx), SqlQuery) != primary {
+		t.Fatal("forced read must go to primary")
+	}
+	session := WithReadYourWrites(ctx)
+	if router.Get(session, SqlQuery) != r1 {
+		t.Fatal("read before write must go to replica")
+	}
+	router.Get(session, SqlMutation)
+	if router.Get(session, SqlQuery) != primary {
+		t.Fatal("read after write must go to primary")
+	}
+	r1.pingErr = errors.New("down")
+	router.CheckHealth(ctx)
+	if router.Get(ctx, SqlQuery) != primary {
+		t.Fatal("read must fall back to primary")
+	}
+}
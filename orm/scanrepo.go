@@ -2,13 +2,22 @@ package orm
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
 )
 
 type scannerCallOptions[F fieldAlias, S targeter[F]] struct {
 	excludeFields  []F
 	conflictFields []F
+	noUpdateFields []F
 	copyFields     []F
-	doNothing      bool
+	returning      []F
+	timeout        time.Duration
+	deleted        deletedScope
+	version        any
 }
 
 type ScannerCallOptions[F fieldAlias, S targeter[F]] interface {
@@ -21,6 +30,8 @@ func (f ScannerCallOptionsFn[F, S]) apply(opts *scannerCallOptions[F, S]) {
 	f(opts)
 }
 
+// WithScannerExcludeFields — columns left out of every write: INSERT, UPDATE
+// and DO UPDATE SET of upserts
 func WithScannerExcludeFields[F fieldAlias, S targeter[F]](fields ...F) ScannerCallOptionsFn[F, S] {
 	return func(opts *scannerCallOptions[F, S]) {
 		opts.excludeFields = fields
@@ -39,6 +50,52 @@ func WithScannerConflictFields[F fieldAlias, S targeter[F]](fields ...F) Scanner
 	}
 }
 
+// WithScannerReturningFields — columns returned by *Ret methods, all by default
+func WithScannerReturningFields[F fieldAlias, S targeter[F]](fields ...F) ScannerCallOptionsFn[F, S] {
+	return func(opts *scannerCallOptions[F, S]) {
+		opts.returning = fields
+	}
+}
+
+// WithScannerNoUpdateFields — columns inserted by upserts but kept on
+// conflict, e.g. created_by
+func WithScannerNoUpdateFields[F fieldAlias, S targeter[F]](fields ...F) ScannerCallOptionsFn[F, S] {
+	return func(opts *scannerCallOptions[F, S]) {
+		opts.noUpdateFields = fields
+	}
+}
+
+// WithScannerStatementTimeout — client-side deadline of the call
+// (context.WithTimeout), pgx cancels the running statement when it expires;
+// PostgreSQL statement_timeout is not set
+func WithScannerStatementTimeout[F fieldAlias, S targeter[F]](timeout time.Duration) ScannerCallOptionsFn[F, S] {
+	return func(opts *scannerCallOptions[F, S]) {
+		opts.timeout = timeout
+	}
+}
+
+// WithScannerDeleted — reads include soft deleted rows
+func WithScannerDeleted[F fieldAlias, S targeter[F]]() ScannerCallOptionsFn[F, S] {
+	return func(opts *scannerCallOptions[F, S]) {
+		opts.deleted = includeDeleted
+	}
+}
+
+// WithScannerOnlyDeleted — reads match soft deleted rows only
+func WithScannerOnlyDeleted[F fieldAlias, S targeter[F]]() ScannerCallOptionsFn[F, S] {
+	return func(opts *scannerCallOptions[F, S]) {
+		opts.deleted = onlyDeleted
+	}
+}
+
+// WithScannerExpectedVersion — version checked by updates of versioned tables
+// instead of the version of the entity
+func WithScannerExpectedVersion[F fieldAlias, S targeter[F]](version any) ScannerCallOptionsFn[F, S] {
+	return func(opts *scannerCallOptions[F, S]) {
+		opts.version = version
+	}
+}
+
 type ScannerRepository[F fieldAlias, S targeter[F]] interface {
 	Table() TableI[F, S]
 	Insert(ctx context.Context, entity S, opts ...ScannerCallOptions[F, S]) error
@@ -46,14 +103,39 @@ type ScannerRepository[F fieldAlias, S targeter[F]] interface {
 	InsertMany(ctx context.Context, entities []S, opts ...ScannerCallOptions[F, S]) error
 	Update(ctx context.Context, entity S, clause Clause[F], opts ...ScannerCallOptions[F, S]) error
 	UpdateRet(ctx context.Context, entity S, clause Clause[F], opts ...ScannerCallOptions[F, S]) (S, error)
-	//Upsert(ctx context.Context, entity S, conflictFields []F, opts ...ScannerCallOptions[F, S]) error
-	//UpsertRet(ctx context.Context, entity S, conflictFields []F, opts ...ScannerCallOptions[F, S]) (S, error)
-	//UpsertIgnore(ctx context.Context, entity S, opts ...ScannerCallOptions[F, S]) error
+	Upsert(ctx context.Context, entity S, opts ...ScannerCallOptions[F, S]) error
+	UpsertRet(ctx context.Context, entity S, opts ...ScannerCallOptions[F, S]) (S, error)
+	UpsertIgnore(ctx context.Context, entity S, opts ...ScannerCallOptions[F, S]) error
+	UpsertMany(ctx context.Context, entities []S, opts ...ScannerCallOptions[F, S]) error
+	UpsertIgnoreMany(ctx context.Context, entities []S, opts ...ScannerCallOptions[F, S]) error
+
+	Delete(ctx context.Context, clause Clause[F], opts ...ScannerCallOptions[F, S]) error
+	HardDelete(ctx context.Context, clause Clause[F], opts ...ScannerCallOptions[F, S]) error
+	Restore(ctx context.Context, clause Clause[F], opts ...ScannerCallOptions[F, S]) error
+	Exists(ctx context.Context, clause Clause[F], opts ...ScannerCallOptions[F, S]) (bool, error)
 
 	GetBy(ctx context.Context, query ormQuery, opts ...ScannerCallOptions[F, S]) (S, error)
 	ListBy(ctx context.Context, query ormQuery, opts ...ScannerCallOptions[F, S]) ([]S, error)
+	Stream(ctx context.Context, query ormQuery, opts ...ScannerCallOptions[F, S]) iter.Seq2[S, error]
+	ListPage(
+		ctx context.Context,
+		query *SelectQuery[F],
+		token string,
+		size int,
+		opts ...ScannerCallOptions[F, S],
+	) ([]S, string, error)
 	Exec(ctx context.Context, query ormQuery, opts ...ScannerCallOptions[F, S]) error
 	ExecAffected(ctx context.Context, query ormQuery, opts ...ScannerCallOptions[F, S]) (int64, error)
+
+	BatchInsert(b *Batch, entity S, opts ...ScannerCallOptions[F, S]) *BatchResult[int64]
+	BatchInsertRet(b *Batch, entity S, opts ...ScannerCallOptions[F, S]) *BatchResult[S]
+	BatchUpdate(b *Batch, entity S, clause Clause[F], opts ...ScannerCallOptions[F, S]) *BatchResult[int64]
+	BatchUpdateRet(b *Batch, entity S, clause Clause[F], opts ...ScannerCallOptions[F, S]) *BatchResult[S]
+	BatchUpsert(b *Batch, entity S, opts ...ScannerCallOptions[F, S]) *BatchResult[int64]
+	BatchDelete(b *Batch, clause Clause[F]) *BatchResult[int64]
+	BatchGetBy(b *Batch, query ormQuery, opts ...ScannerCallOptions[F, S]) *BatchResult[S]
+	BatchListBy(b *Batch, query ormQuery, opts ...ScannerCallOptions[F, S]) *BatchResult[[]S]
+	BatchExec(b *Batch, query ormQuery) *BatchResult[int64]
 }
 
 func GetFieldsSetters[F fieldAlias, S targeter[F]](model S, fields ...F) []ValueSetter[F] {
@@ -92,52 +174,118 @@ func (g *genericScannerRepository[F, S]) Table() TableI[F, S] {
 }
 
 func (g *genericScannerRepository[F, S]) opts(opts ...ScannerCallOptions[F, S]) *scannerCallOptions[F, S] {
-	op := &scannerCallOptions[F, S]{
-		excludeFields: g.table.allFields,
-		copyFields:    g.table.allFields,
-	}
+	op := &scannerCallOptions[F, S]{}
 	for _, o := range opts {
 		o.apply(op)
 	}
 	return op
 }
 
+// writeFields — columns written by INSERT/UPDATE: all fields except excluded
+// and the soft delete column, which is set by Delete and Restore only
+func (g *genericScannerRepository[F, S]) writeFields(opt *scannerCallOptions[F, S]) []F {
+	if len(opt.excludeFields) == 0 {
+		return g.table.writeFields()
+	}
+	return exceptFields(g.table.writeFields(), opt.excludeFields)
+}
+
+func (g *genericScannerRepository[F, S]) returningFields(opt *scannerCallOptions[F, S]) []F {
+	if len(opt.returning) > 0 {
+		return opt.returning
+	}
+	return g.table.allFields
+}
+
+func (g *genericScannerRepository[F, S]) withTimeout(
+	ctx context.Context,
+	opt *scannerCallOptions[F, S],
+) (context.Context, context.CancelFunc) {
+	if opt.timeout > 0 {
+		return context.WithTimeout(ctx, opt.timeout)
+	}
+	return ctx, func() {}
+}
+
+func exceptFields[F fieldAlias](fields []F, except []F) []F {
+	ret := make([]F, 0, len(fields))
+	for _, f := range fields {
+		if !slices.ContainsFunc(except, func(e F) bool { return e.String() == f.String() }) {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+func (g *genericScannerRepository[F, S]) insertQuery(opt *scannerCallOptions[F, S], entity S) *InsertQuery[F] {
+	return g.table.Insert().From(GetFieldsSetters(entity, g.writeFields(opt)...)...)
+}
+
+func (g *genericScannerRepository[F, S]) updateQuery(
+	opt *scannerCallOptions[F, S],
+	entity S,
+	clause Clause[F],
+) *UpdateQuery[F] {
+	query := g.table.Update().
+		Set(GetFieldsSetters(entity, g.writeFields(opt)...)...).
+		Set(g.table.managedUpdates()...).
+		Where(clause)
+	if g.table.version != nil {
+		query.Where(g.table.versionClause(entity, opt.version))
+	}
+	return query
+}
+
 func (g *genericScannerRepository[F, S]) Insert(
 	ctx context.Context,
 	entity S,
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) error {
-	_, err := g.table.Execute(
-		ctx,
-		g.dbGetter(ctx, SqlMutation),
-		g.table.Insert().From(GetFieldsSetters(entity, g.table.allFields...)...),
-	)
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	if g.table.createdAt != nil {
+		query := g.insertQuery(opt, entity).Returning(*g.table.createdAt, *g.table.updatedAt)
+		_, err := g.table.execTimestamps(ctx, g.dbGetter(ctx, SqlMutation), query, entity)
+		return err
+	}
+	_, err := g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), g.insertQuery(opt, entity))
 	return err
 }
 
 func (g *genericScannerRepository[F, S]) InsertRet(
 	ctx context.Context,
 	entity S,
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) (S, error) {
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
 	return g.table.QueryRow(
 		ctx,
 		g.dbGetter(ctx, SqlMutation),
-		g.table.Insert().From(GetFieldsSetters(entity, g.table.allFields...)...).ReturningAll(),
+		g.insertQuery(opt, entity).Returning(g.returningFields(opt)...),
 	)
 }
 
+// InsertMany — COPY of WithScannerCopyFields columns, written columns by default
 func (g *genericScannerRepository[F, S]) InsertMany(
 	ctx context.Context,
 	entities []S,
 	opts ...ScannerCallOptions[F, S],
 ) error {
 	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	fields := opt.copyFields
+	if len(fields) == 0 {
+		fields = g.writeFields(opt)
+	}
 	_, err := g.table.CopyFrom(
 		ctx,
 		g.dbGetter(ctx, SqlMutation),
 		entities,
-		opt.copyFields...,
+		fields...,
 	)
 	return err
 }
@@ -146,136 +294,327 @@ func (g *genericScannerRepository[F, S]) Update(
 	ctx context.Context,
 	entity S,
 	clause Clause[F],
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) error {
-	_, err := g.table.Execute(
-		ctx,
-		g.dbGetter(ctx, SqlMutation),
-		g.table.Update().Set(GetFieldsSetters(entity, g.table.allFields...)...).Where(clause),
-	)
-	return err
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	if g.table.createdAt != nil {
+		query := g.updateQuery(opt, entity, clause).Returning(*g.table.createdAt, *g.table.updatedAt)
+		affected, err := g.table.execTimestamps(ctx, g.dbGetter(ctx, SqlMutation), query, entity)
+		return g.table.staleAffected(affected, err)
+	}
+	affected, err := g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), g.updateQuery(opt, entity, clause))
+	return g.table.staleAffected(affected, err)
 }
 
 func (g *genericScannerRepository[F, S]) UpdateRet(
 	ctx context.Context,
 	entity S,
 	clause Clause[F],
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) (S, error) {
-	return g.table.QueryRow(
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	ret, err := g.table.QueryRow(
 		ctx,
 		g.dbGetter(ctx, SqlMutation),
-		g.table.Update().Set(GetFieldsSetters(entity, g.table.allFields...)...).Where(clause).ReturningAll(),
+		g.updateQuery(opt, entity, clause).Returning(g.returningFields(opt)...),
 	)
+	return ret, g.table.staleError(err)
+}
+
+// conflictTarget — WithScannerConflictFields, else primary key, else the
+// first unique key of the table
+func (g *genericScannerRepository[F, S]) conflictTarget(opt *scannerCallOptions[F, S]) []F {
+	switch {
+	case len(opt.conflictFields) > 0:
+		return opt.conflictFields
+	case len(g.table.primaryKey) > 0:
+		return g.table.primaryKey
+	case len(g.table.uniqueKeys) > 0:
+		return g.table.uniqueKeys[0]
+	}
+	return nil
 }
 
-//
-//func (g *genericScannerRepository[F, S]) Upsert(
-//	ctx context.Context,
-//	entity S,
-//	conflictFields []F,
-//	opts ...ScannerCallOptions[F, S],
-//) error {
-//	opt := g.opts(opts...)
-//	if len(opt.excludeFields) == 0 {
-//		return errors.Join(ErrEmptyFields, errors.New("excluded fields are empty for upsert"))
-//	}
-//	if len(conflictFields) == 0 {
-//		return errors.Join(ErrEmptyFields, errors.New("conflict fields are empty for upsert"))
-//	}
-//	_, err := g.table.Execute(
-//		ctx,
-//		g.dbGetter(ctx, SqlMutation),
-//		g.table.Insert().
-//			From(getFieldsSetters(
-//				entity,
-//				g.table.allFields...,
-//			)...).
-//			OnConflict(conflictFields...).
-//			DoUpdate(opt.excludeFields...),
-//	)
-//	return err
-//}
-//
-//func (g *genericScannerRepository[F, S]) UpsertRet(
-//	ctx context.Context,
-//	entity S,
-//	conflictFields []F,
-//	opts ...ScannerCallOptions[F, S],
-//) (ret S, err error) {
-//	opt := g.opts(opts...)
-//	if len(opt.excludeFields) == 0 {
-//		return ret, errors.Join(ErrEmptyFields, errors.New("excluded fields are empty for upsert"))
-//	}
-//	if len(conflictFields) == 0 {
-//		return ret, errors.Join(ErrEmptyFields, errors.New("conflict fields are empty for upsert"))
-//	}
-//	return g.table.QueryRow(
-//		ctx,
-//		g.dbGetter(ctx, SqlMutation),
-//		g.table.Insert().
-//			From(getFieldsSetters(
-//				entity,
-//				g.table.allFields...,
-//			)...).
-//			OnConflict(conflictFields...).
-//			DoUpdate(opt.excludeFields...).ReturningAll(),
-//	)
-//}
-//
-//func (g *genericScannerRepository[F, S]) UpsertIgnore(
-//	ctx context.Context,
-//	entity S,
-//	opts ...ScannerCallOptions[F, S],
-//) error {
-//	opt := g.opts(opts...)
-//	if len(opt.conflictFields) == 0 {
-//		return errors.Join(ErrEmptyFields, errors.New("copy fields are empty for upsert ignore"))
-//	}
-//	_, err := g.table.Execute(
-//		ctx,
-//		g.dbGetter(ctx, SqlMutation),
-//		g.table.Insert().
-//			From(getFieldsSetters(
-//				entity,
-//				g.table.allFields...,
-//			)...).
-//			OnConflict(opt.conflictFields...).
-//			DoNothing(),
-//	)
-//	return err
-//}
+// upsertQuery updates every written column except the conflict target and
+// WithScannerNoUpdateFields
+func (g *genericScannerRepository[F, S]) upsertQuery(
+	opt *scannerCallOptions[F, S],
+	entities ...S,
+) (*InsertQuery[F], error) {
+	target := g.conflictTarget(opt)
+	if len(target) == 0 {
+		return nil, errors.Join(ErrEmptyFields, errors.New("conflict fields are empty for upsert"))
+	}
+	updates := exceptFields(exceptFields(g.writeFields(opt), target), opt.noUpdateFields)
+	if len(updates) == 0 {
+		// no-op update keeps RETURNING of the existing row working
+		updates = target
+	}
+	return g.insertRowsQuery(opt, entities).
+		OnConflict(target...).
+		DoUpdate(updates...).
+		DoUpdateSet(g.table.managedUpdates()...), nil
+}
+
+func (g *genericScannerRepository[F, S]) insertRowsQuery(opt *scannerCallOptions[F, S], entities []S) *InsertQuery[F] {
+	fields := g.writeFields(opt)
+	rows := make([][]ValueSetter[F], 0, len(entities))
+	for _, entity := range entities {
+		rows = append(rows, GetFieldsSetters(entity, fields...))
+	}
+	return g.table.Insert().Rows(rows...)
+}
+
+func (g *genericScannerRepository[F, S]) Upsert(
+	ctx context.Context,
+	entity S,
+	opts ...ScannerCallOptions[F, S],
+) error {
+	return g.UpsertMany(ctx, []S{entity}, opts...)
+}
+
+func (g *genericScannerRepository[F, S]) UpsertRet(
+	ctx context.Context,
+	entity S,
+	opts ...ScannerCallOptions[F, S],
+) (ret S, err error) {
+	opt := g.opts(opts...)
+	query, err := g.upsertQuery(opt, entity)
+	if err != nil {
+		return ret, err
+	}
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	return g.table.QueryRow(ctx, g.dbGetter(ctx, SqlMutation), query.Returning(g.returningFields(opt)...))
+}
+
+func (g *genericScannerRepository[F, S]) UpsertIgnore(
+	ctx context.Context,
+	entity S,
+	opts ...ScannerCallOptions[F, S],
+) error {
+	return g.UpsertIgnoreMany(ctx, []S{entity}, opts...)
+}
+
+// UpsertMany — multi-row upsert, entities must not repeat a conflict key
+// because a row can't be affected twice by one statement
+func (g *genericScannerRepository[F, S]) UpsertMany(
+	ctx context.Context,
+	entities []S,
+	opts ...ScannerCallOptions[F, S],
+) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	opt := g.opts(opts...)
+	query, err := g.upsertQuery(opt, entities...)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	_, err = g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), query)
+	return err
+}
+
+// UpsertIgnoreMany — ON CONFLICT DO NOTHING, without conflict target when the
+// table has no keys
+func (g *genericScannerRepository[F, S]) UpsertIgnoreMany(
+	ctx context.Context,
+	entities []S,
+	opts ...ScannerCallOptions[F, S],
+) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	query := g.insertRowsQuery(opt, entities).OnConflict(g.conflictTarget(opt)...).DoNothing()
+	_, err := g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), query)
+	return err
+}
+
+// Delete sets the deletion time of tables with soft delete column and
+// deletes rows of the rest
+func (g *genericScannerRepository[F, S]) Delete(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ScannerCallOptions[F, S],
+) error {
+	return g.Exec(ctx, g.table.deleteQuery(clause), opts...)
+}
+
+// HardDelete deletes rows regardless of soft delete
+func (g *genericScannerRepository[F, S]) HardDelete(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ScannerCallOptions[F, S],
+) error {
+	return g.Exec(ctx, g.table.Delete().Where(clause), opts...)
+}
+
+// Restore clears the deletion time of soft deleted rows
+func (g *genericScannerRepository[F, S]) Restore(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ScannerCallOptions[F, S],
+) error {
+	if g.table.softDelete == nil {
+		return fmt.Errorf("pgx-orm: restore %s: %w", g.table.alias, ErrNoSoftDelete)
+	}
+	return g.Exec(ctx, g.table.Restore().Where(clause), opts...)
+}
+
+func (g *genericScannerRepository[F, S]) Exists(
+	ctx context.Context,
+	clause Clause[F],
+	opts ...ScannerCallOptions[F, S],
+) (bool, error) {
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	query := g.table.Select1().Where(clause).withScope(opt.deleted)
+	return g.table.exists(ctx, g.dbGetter(ctx, SqlQuery), query)
+}
 
 func (g *genericScannerRepository[F, S]) GetBy(
 	ctx context.Context,
 	query ormQuery,
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) (S, error) {
-	return g.table.QueryRow(ctx, g.dbGetter(ctx, SqlQuery), query)
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	return g.table.QueryRow(ctx, g.dbGetter(ctx, SqlQuery), scoped[F](query, opt.deleted))
 }
 
 func (g *genericScannerRepository[F, S]) ListBy(
 	ctx context.Context,
 	query ormQuery,
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) ([]S, error) {
-	return g.table.Query(ctx, g.dbGetter(ctx, SqlQuery), query)
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	return g.table.Query(ctx, g.dbGetter(ctx, SqlQuery), scoped[F](query, opt.deleted))
+}
+
+// Stream — ListBy without loading all rows into memory, the statement
+// timeout covers the whole iteration
+func (g *genericScannerRepository[F, S]) Stream(
+	ctx context.Context,
+	query ormQuery,
+	opts ...ScannerCallOptions[F, S],
+) iter.Seq2[S, error] {
+	opt := g.opts(opts...)
+	return func(yield func(S, error) bool) {
+		ctx, cancel := g.withTimeout(ctx, opt)
+		defer cancel()
+		for row, err := range g.table.Iter(ctx, g.dbGetter(ctx, SqlQuery), scoped[F](query, opt.deleted)) {
+			if !yield(row, err) {
+				return
+			}
+		}
+	}
+}
+
+// ListPage — keyset page of query, see table.QueryPage
+func (g *genericScannerRepository[F, S]) ListPage(
+	ctx context.Context,
+	query *SelectQuery[F],
+	token string,
+	size int,
+	opts ...ScannerCallOptions[F, S],
+) ([]S, string, error) {
+	opt := g.opts(opts...)
+	ctx, cancel := g.withTimeout(ctx, opt)
+	defer cancel()
+	return g.table.QueryPage(ctx, g.dbGetter(ctx, SqlQuery), scoped[F](query, opt.deleted).(*SelectQuery[F]), token, size)
 }
 
 func (g *genericScannerRepository[F, S]) Exec(
 	ctx context.Context,
 	query ormQuery,
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) error {
-	_, err := g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), query)
+	_, err := g.ExecAffected(ctx, query, opts...)
 	return err
 }
 
 func (g *genericScannerRepository[F, S]) ExecAffected(
 	ctx context.Context,
 	query ormQuery,
-	_ ...ScannerCallOptions[F, S],
+	opts ...ScannerCallOptions[F, S],
 ) (int64, error) {
-	affected, err := g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), query)
-	return affected, err
+	ctx, cancel := g.withTimeout(ctx, g.opts(opts...))
+	defer cancel()
+	return g.table.Execute(ctx, g.dbGetter(ctx, SqlMutation), query)
+}
+
+// ---------------------------------------------------------------------------
+// batch variants, results are available after Batch.Send ---------------------
+// ---------------------------------------------------------------------------
+
+func (g *genericScannerRepository[F, S]) BatchInsert(b *Batch, entity S, opts ...ScannerCallOptions[F, S]) *BatchResult[int64] {
+	return g.table.QueueExec(b, g.insertQuery(g.opts(opts...), entity))
+}
+
+func (g *genericScannerRepository[F, S]) BatchInsertRet(b *Batch, entity S, opts ...ScannerCallOptions[F, S]) *BatchResult[S] {
+	opt := g.opts(opts...)
+	return g.table.QueueQueryRow(b, g.insertQuery(opt, entity).Returning(g.returningFields(opt)...))
+}
+
+func (g *genericScannerRepository[F, S]) BatchUpdate(
+	b *Batch,
+	entity S,
+	clause Clause[F],
+	opts ...ScannerCallOptions[F, S],
+) *BatchResult[int64] {
+	return staleBatchResult(
+		g.table.QueueExec(b, g.updateQuery(g.opts(opts...), entity, clause)),
+		g.table.staleAffected,
+	)
+}
+
+func (g *genericScannerRepository[F, S]) BatchUpdateRet(
+	b *Batch,
+	entity S,
+	clause Clause[F],
+	opts ...ScannerCallOptions[F, S],
+) *BatchResult[S] {
+	opt := g.opts(opts...)
+	return staleBatchResult(
+		g.table.QueueQueryRow(b, g.updateQuery(opt, entity, clause).Returning(g.returningFields(opt)...)),
+		func(_ S, err error) error { return g.table.staleError(err) },
+	)
+}
+
+func (g *genericScannerRepository[F, S]) BatchUpsert(b *Batch, entity S, opts ...ScannerCallOptions[F, S]) *BatchResult[int64] {
+	query, err := g.upsertQuery(g.opts(opts...), entity)
+	if err != nil {
+		return failedBatchResult[int64](err)
+	}
+	return g.table.QueueExec(b, query)
+}
+
+// BatchDelete takes no options, the statement timeout is the ctx of Batch.Send
+func (g *genericScannerRepository[F, S]) BatchDelete(b *Batch, clause Clause[F]) *BatchResult[int64] {
+	return g.table.QueueExec(b, g.table.deleteQuery(clause))
+}
+
+func (g *genericScannerRepository[F, S]) BatchGetBy(b *Batch, query ormQuery, opts ...ScannerCallOptions[F, S]) *BatchResult[S] {
+	return g.table.QueueQueryRow(b, scoped[F](query, g.opts(opts...).deleted))
+}
+
+func (g *genericScannerRepository[F, S]) BatchListBy(b *Batch, query ormQuery, opts ...ScannerCallOptions[F, S]) *BatchResult[[]S] {
+	return g.table.QueueQuery(b, scoped[F](query, g.opts(opts...).deleted))
+}
+
+func (g *genericScannerRepository[F, S]) BatchExec(b *Batch, query ormQuery) *BatchResult[int64] {
+	return g.table.QueueExec(b, query)
 }
@@ -0,0 +1,125 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUpsertConflictTarget(t *testing.T) {
+	id, email, name := testField("id"), testField("email"), testField("name")
+	entity := testScanner{"id": 1, "email": "a@b", "name": "a"}
+	tests := []struct {
+		name  string
+		table *table[fieldAlias, testScanner]
+		opts  []ScannerCallOptions[fieldAlias, testScanner]
+		want  string
+	}{
+		{
+			name:  "option",
+			table: newTestTable("id", "email", "name").withPrimaryKey(id).withUnique(email),
+			opts:  []ScannerCallOptions[fieldAlias, testScanner]{WithScannerConflictFields[fieldAlias, testScanner](name)},
+			want:  "INSERT INTO users (id, email, name) VALUES ($1, $2, $3) ON CONFLICT (name) DO UPDATE SET id=EXCLUDED.id, email=EXCLUDED.email;",
+		},
+		{
+			name:  "primary key",
+			table: newTestTable("id", "email", "name").withPrimaryKey(id).withUnique(email),
+			want:  "INSERT INTO users (id, email, name) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET email=EXCLUDED.email, name=EXCLUDED.name;",
+		},
+		{
+			name:  "first unique key",
+			table: newTestTable("id", "email", "name").withUnique(email).withUnique(name),
+			want:  "INSERT INTO users (id, email, name) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET id=EXCLUDED.id, name=EXCLUDED.name;",
+		},
+		{
+			name:  "excluded fields",
+			table: newTestTable("id", "email", "name").withPrimaryKey(id),
+			opts:  []ScannerCallOptions[fieldAlias, testScanner]{WithScannerExcludeFields[fieldAlias, testScanner](name)},
+			want:  "INSERT INTO users (id, email) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET email=EXCLUDED.email;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newGenericScannerRepository(tt.table, nil)
+			query, err := repo.upsertQuery(repo.opts(tt.opts...), entity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql, _ := query.Build(); sql != tt.want {
+				t.Fatalf("upsert SQL fail:\nwant: %s\ngot : %s", tt.want, sql)
+			}
+		})
+	}
+
+	repo := newGenericScannerRepository(newTestTable("id", "name"), nil)
+	if _, err := repo.upsertQuery(repo.opts(), entity); !errors.Is(err, ErrEmptyFields) {
+		t.Fatalf("table without keys must fail: %v", err)
+	}
+}
+
+func TestUpsertBatchParity(t *testing.T) {
+	tb := newTestTable("id", "name").withPrimaryKey(testField("id"))
+	db := &testExecDB{}
+	repo := newGenericScannerRepository(tb, NewDbGetter(db))
+	entity := testScanner{"id": 1, "name": "a"}
+	opt := WithScannerExcludeFields[fieldAlias, testScanner](testField("name"))
+
+	if err := repo.Upsert(context.Background(), entity, opt); err != nil {
+		t.Fatal(err)
+	}
+	b := NewBatch()
+	repo.BatchUpsert(b, entity, opt)
+	if batch, _ := b.items[0].query.Build(); len(db.sql) != 1 || db.sql[0] != batch {
+		t.Fatalf("single and batch upsert differ:\nsingle: %v\nbatch : %s", db.sql, batch)
+	}
+}
+
+type testDeadlineDB struct {
+	testExecDB
+	deadline bool
+}
+
+func (d *testDeadlineDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	_, d.deadline = ctx.Deadline()
+	return d.testExecDB.Exec(ctx, sql, args...)
+}
+
+func TestScannerCallOptions(t *testing.T) {
+	id, name, createdBy := testField("id"), testField("name"), testField("created_by")
+	tb := newTestTable("id", "name", "created_by").withPrimaryKey(id)
+	db := &testDeadlineDB{}
+	repo := newGenericScannerRepository(tb, NewDbGetter(db))
+	entity := testScanner{"id": 1, "name": "a", "created_by": "u"}
+	clause := &FieldClause[fieldAlias]{Field: id, Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}}
+	exclude := WithScannerExcludeFields[fieldAlias, testScanner](name)
+	ctx := context.Background()
+
+	if err := repo.Insert(ctx, entity, exclude, WithScannerStatementTimeout[fieldAlias, testScanner](time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if !db.deadline {
+		t.Fatal("statement timeout must set the deadline")
+	}
+	if err := repo.Update(ctx, entity, clause, exclude); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Upsert(ctx, entity, WithScannerNoUpdateFields[fieldAlias, testScanner](createdBy)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"INSERT INTO users (id, created_by) VALUES ($1, $2);",
+		"UPDATE users SET id = $1, created_by = $2 WHERE users.id = $3;",
+		"INSERT INTO users (id, name, created_by) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name;",
+	}
+	if !slices.Equal(db.sql, want) {
+		t.Fatalf("options are not honoured:\nwant: %q\ngot : %q", want, db.sql)
+	}
+
+	query := repo.insertQuery(repo.opts(), entity).Returning(repo.returningFields(repo.opts(WithScannerReturningFields[fieldAlias, testScanner](id)))...)
+	if sql, _ := query.Build(); sql != "INSERT INTO users (id, name, created_by) VALUES ($1, $2, $3) RETURNING id;" {
+		t.Fatalf("returning fields fail: %s", sql)
+	}
+}
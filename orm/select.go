@@ -8,15 +8,20 @@ import (
 type SelectQuery[F fieldAlias] struct {
 	baseQuery[F]
 	distinct     bool
+	from         *SubQueryExprClause[F]
 	whereClauses []Clause[F]
 	groupBy      []F
-	orderByASC   []F
-	orderByDESC  []F
+	windows      []namedWindow[F]
+	windowFields []*WindowExpr[F]
+	orderBy      []OrderTerm[F]
+	softDelete   *softDeleteScope[F]
 	limit        int
 	offset       int
 	forUpdate    bool
 }
 
+func (q *SelectQuery[F]) readOnly() {}
+
 func (q *SelectQuery[F]) Build() (string, []any) {
 	i := 1
 	// берём буфер из пула
@@ -24,11 +29,12 @@ func (q *SelectQuery[F]) Build() (string, []any) {
 	sb.Reset()
 
 	// heuristics: 128 базовый + ~32 на каждый where/order/group + ~16 на поле
-	approxCap := 128 + (len(q.whereClauses)+len(q.orderByASC)+len(q.orderByDESC)+len(q.groupBy))*32 + len(q.usingFields)*16
+	approxCap := 128 + (len(q.whereClauses)+len(q.orderBy)+len(q.groupBy))*32 + len(q.usingFields)*16
 	sb.Grow(approxCap)
 
 	args := make([]any, 0, len(q.whereClauses)*2) // простой грубый estimate
 	q.build(sb, q.tableAlias(), &i, &args)
+	sb.WriteByte(';')
 	sql := sb.String() // копия в новую строку
 	sbPool.Put(sb)
 	return sql, args
@@ -36,14 +42,16 @@ func (q *SelectQuery[F]) Build() (string, []any) {
 
 //goland:noinspection t
 func (q *SelectQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int, args *[]any) {
-	inSubquery := buf.Len() > 0 || *paramIndex > 1 || len(*args) > 0
 	// ---------- SELECT ----------
 	buf.WriteString("SELECT ")
 	if q.distinct {
 		buf.WriteString("DISTINCT ")
 	}
-	if len(q.usingFields) > 0 {
+	if len(q.usingFields)+len(q.windowFields) > 0 {
 		for i, f := range q.usingFields {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
 			if f.IsCount() {
 				buf.WriteString("COUNT(")
 				buf.WriteString(ta)
@@ -52,27 +60,34 @@ func (q *SelectQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 				buf.WriteString(")")
 				continue
 			}
-			if i > 0 {
-				buf.WriteString(", ")
-			}
 			buf.WriteString(ta)
 			buf.WriteByte('.')
 			buf.WriteString(f.String())
 		}
+		for i, w := range q.windowFields {
+			if i > 0 || len(q.usingFields) > 0 {
+				buf.WriteString(", ")
+			}
+			w.buildSelect(buf, ta, paramIndex, args)
+		}
 	} else {
 		buf.WriteByte('1')
 	}
 
 	// ---------- FROM ----------
 	buf.WriteString(" FROM ")
-	buf.WriteString(ta)
+	if q.from != nil {
+		q.from.build(buf, ta, paramIndex, args)
+	} else {
+		buf.WriteString(ta)
+	}
 	buf.WriteString(" AS ")
 	buf.WriteString(ta)
 
 	// ---------- WHERE ----------
-	if len(q.whereClauses) > 0 {
+	if where := q.where(); len(where) > 0 {
 		buf.WriteString(" WHERE ")
-		for i, clause := range q.whereClauses {
+		for i, clause := range where {
 			if i > 0 {
 				buf.WriteString(" AND ")
 			}
@@ -93,29 +108,28 @@ func (q *SelectQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 		}
 	}
 
-	// ---------- ORDER BY ----------
-	if len(q.orderByASC) > 0 || len(q.orderByDESC) > 0 {
-		buf.WriteString(" ORDER BY ")
-		first := true
-		for _, f := range q.orderByASC {
-			if !first {
+	// ---------- WINDOW ----------
+	if len(q.windows) > 0 {
+		buf.WriteString(" WINDOW ")
+		for i, w := range q.windows {
+			if i > 0 {
 				buf.WriteString(", ")
 			}
-			buf.WriteString(ta)
-			buf.WriteByte('.')
-			buf.WriteString(f.String())
-			buf.WriteString(" ASC")
-			first = false
+			buf.WriteString(w.name)
+			buf.WriteString(" AS (")
+			w.spec.build(buf, ta, paramIndex, args)
+			buf.WriteByte(')')
 		}
-		for _, f := range q.orderByDESC {
-			if !first {
+	}
+
+	// ---------- ORDER BY ----------
+	if len(q.orderBy) > 0 {
+		buf.WriteString(" ORDER BY ")
+		for i, o := range q.orderBy {
+			if i > 0 {
 				buf.WriteString(", ")
 			}
-			buf.WriteString(ta)
-			buf.WriteByte('.')
-			buf.WriteString(f.String())
-			buf.WriteString(" DESC")
-			first = false
+			o.build(buf, ta)
 		}
 	}
 
@@ -133,9 +147,6 @@ func (q *SelectQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 	if q.forUpdate {
 		buf.WriteString(" FOR UPDATE")
 	}
-	if !inSubquery {
-		buf.WriteByte(';')
-	}
 }
 func (q *SelectQuery[F]) Fields(
 	fields ...F,
@@ -143,6 +154,12 @@ func (q *SelectQuery[F]) Fields(
 	q.usingFields = fields
 	return q
 }
+
+// WindowFields — window expressions selected after the fields
+func (q *SelectQuery[F]) WindowFields(exprs ...*WindowExpr[F]) *SelectQuery[F] {
+	q.windowFields = append(q.windowFields, exprs...)
+	return q
+}
 func (q *SelectQuery[F]) Distinct() *SelectQuery[F] {
 	q.distinct = true
 	return q
@@ -153,6 +170,17 @@ func (q *SelectQuery[F]) Alias(
 	q.ta = alias
 	return q
 }
+
+// FromSubQuery — select from (query) AS alias instead of the table itself,
+// e.g. to filter on window expressions of the inner query
+func (q *SelectQuery[F]) FromSubQuery(query ormQuery) *SelectQuery[F] {
+	q.from = &SubQueryExprClause[F]{Query: query}
+	return q
+}
+func (q *SelectQuery[F]) Window(name string, spec *WindowSpec[F]) *SelectQuery[F] {
+	q.windows = append(q.windows, namedWindow[F]{name: name, spec: spec})
+	return q
+}
 func (q *SelectQuery[F]) Where(clause ...Clause[F]) *SelectQuery[F] {
 	q.whereClauses = append(q.whereClauses, clause...)
 	return q
@@ -162,11 +190,41 @@ func (q *SelectQuery[F]) GroupBy(fields ...F) *SelectQuery[F] {
 	return q
 }
 func (q *SelectQuery[F]) OrderByASC(fields ...F) *SelectQuery[F] {
-	q.orderByASC = append(q.orderByASC, fields...)
+	for _, f := range fields {
+		q.orderBy = append(q.orderBy, Asc(f))
+	}
 	return q
 }
 func (q *SelectQuery[F]) OrderByDESC(fields ...F) *SelectQuery[F] {
-	q.orderByDESC = append(q.orderByDESC, fields...)
+	for _, f := range fields {
+		q.orderBy = append(q.orderBy, Desc(f))
+	}
+	return q
+}
+
+// OrderBy — mixed directions in the given order, e.g. OrderBy(Users.Name.Desc(), Users.Id.Asc())
+func (q *SelectQuery[F]) OrderBy(terms ...OrderTerm[F]) *SelectQuery[F] {
+	q.orderBy = append(q.orderBy, terms...)
+	return q
+}
+
+// Paginate — keyset pagination: orders by terms and selects pageSize rows
+// after cursor, the values of terms of the last row of the previous page.
+// A nil cursor selects the first page. Terms should end with a unique column
+// so that no rows are skipped. NULL values are placed by the NULLS order of
+// terms, a nil cursor value stands for NULL.
+func (q *SelectQuery[F]) Paginate(cursor []any, pageSize int, terms ...OrderTerm[F]) *SelectQuery[F] {
+	return q.paginate(cursor, pageSize, nil, terms...)
+}
+
+// paginate — Paginate knowing notNull columns, e.g. the primary key, which
+// keep the row comparison form of the keyset
+func (q *SelectQuery[F]) paginate(cursor []any, pageSize int, notNull []F, terms ...OrderTerm[F]) *SelectQuery[F] {
+	if cursor != nil {
+		q.whereClauses = append(q.whereClauses, &keysetClause[F]{terms: terms, values: cursor, notNull: notNull})
+	}
+	q.orderBy = terms
+	q.limit = pageSize
 	return q
 }
 func (q *SelectQuery[F]) Limit(limit int) *SelectQuery[F] {
@@ -187,10 +245,13 @@ func (q *SelectQuery[F]) SetLimit(limit int) {
 func (q *SelectQuery[F]) SetOffset(offset int) {
 	q.offset = offset
 }
+func (q *SelectQuery[F]) SetOrderTerms(terms ...OrderTerm[F]) {
+	q.orderBy = append(q.orderBy, terms...)
+}
 func (q *SelectQuery[F]) SetOrderBy(asc bool, fields ...F) {
 	if asc {
-		q.orderByASC = append(q.orderByASC, fields...)
+		q.OrderByASC(fields...)
 	} else {
-		q.orderByDESC = append(q.orderByDESC, fields...)
+		q.OrderByDESC(fields...)
 	}
 }
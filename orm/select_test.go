@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestSelect(fields ...fieldAlias) *SelectQuery[fieldAlias] {
+	return &SelectQuery[fieldAlias]{baseQuery: baseQuery[fieldAlias]{ta: "posts", usingFields: fields}}
+}
+
+func TestWindowSelect(t *testing.T) {
+	rn := newWindowExpr[fieldAlias]("ROW_NUMBER").
+		PartitionBy(testField("user_id")).
+		OrderByDESC(testField("created_at")).
+		As("rn")
+	sql, _ := newTestSelect(testField("id")).WindowFields(rn).Build()
+	want := "SELECT posts.id, ROW_NUMBER() OVER (PARTITION BY posts.user_id ORDER BY posts.created_at DESC) AS rn FROM posts AS posts"
+	if !strings.Contains(sql, want) {
+		t.Fatalf("window fail:\nwant: %s\ngot : %s", want, sql)
+	}
+
+	lag := newFieldWindowExpr[fieldAlias]("LAG", testField("score"), 1).Over("w").As("prev")
+	spec := (&WindowSpec[fieldAlias]{}).PartitionBy(testField("user_id")).OrderByASC(testField("id"))
+	sql, _ = newTestSelect(testField("id")).WindowFields(lag).Window("w", spec).Build()
+	if !strings.Contains(sql, "LAG(posts.score, 1) OVER w AS prev") {
+		t.Fatalf("named window fail: %s", sql)
+	}
+	if !strings.Contains(sql, "WINDOW w AS (PARTITION BY posts.user_id ORDER BY posts.id ASC)") {
+		t.Fatalf("window clause fail: %s", sql)
+	}
+}
+
+func TestWindowFilterBySubQuery(t *testing.T) {
+	rn := newWindowExpr[fieldAlias]("ROW_NUMBER").PartitionBy(testField("user_id")).As("rn")
+	inner := newTestSelect(testField("id")).WindowFields(rn).Where(&FieldClause[fieldAlias]{
+		Field: testField("title"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: "x"},
+	})
+	sql, args := newTestSelect(testField("id")).FromSubQuery(inner).Where(rn.Column().Lte(3)).Build()
+	want := "SELECT posts.id FROM (SELECT posts.id, ROW_NUMBER() OVER (PARTITION BY posts.user_id) AS rn " +
+		"FROM posts AS posts WHERE posts.title = $1) AS posts WHERE posts.rn <= $2"
+	if !strings.Contains(sql, want) {
+		t.Fatalf("subquery fail:\nwant: %s\ngot : %s", want, sql)
+	}
+	if len(args) != 2 || args[0] != "x" || args[1] != 3 {
+		t.Fatalf("args mismatch: %v", args)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	eq := func(f string, v any) Clause[fieldAlias] {
+		return &FieldClause[fieldAlias]{Field: testField(f), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: v}}
+	}
+	left := newTestSelect(testField("id"), testField("title")).Where(eq("user_id", 1))
+	right := newTestSelect(testField("id"), testField("title")).Where(eq("user_id", 2)).Limit(5)
+	third := newTestSelect(testField("id"), testField("title")).Where(eq("title", "spam"))
+	sql, args := left.UnionAll(right).Except(third).OrderByDESC(testField("id")).Limit(10).Build()
+	want := "(SELECT posts.id, posts.title FROM posts AS posts WHERE posts.user_id = $1) UNION ALL " +
+		"(SELECT posts.id, posts.title FROM posts AS posts WHERE posts.user_id = $2 LIMIT 5) EXCEPT " +
+		"(SELECT posts.id, posts.title FROM posts AS posts WHERE posts.title = $3) ORDER BY id DESC LIMIT 10;"
+	if sql != want {
+		t.Fatalf("set operations fail:\nwant: %s\ngot : %s", want, sql)
+	}
+	if len(args) != 3 || args[2] != "spam" {
+		t.Fatalf("args mismatch: %v", args)
+	}
+}
+
+func TestSubQueryIsNotTerminated(t *testing.T) {
+	inner := newTestSelect(testField("id"))
+	sql, _ := newTestSelect(testField("id")).Where(&FieldClause[fieldAlias]{
+		Field: testField("id"), Operator: "IN", Right: &SubQueryExprClause[fieldAlias]{Query: inner},
+	}).Build()
+	if strings.Count(sql, ";") != 1 || !strings.HasSuffix(sql, ";") {
+		t.Fatalf("terminator fail: %s", sql)
+	}
+}
@@ -0,0 +1,134 @@
+package orm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// UNION / INTERSECT / EXCEPT -------------------------------------------------
+// ---------------------------------------------------------------------------
+
+type setOperation[F fieldAlias] struct {
+	operator string
+	query    *SelectQuery[F]
+}
+
+// SetQuery — selects combined with set operators. Operands must select the
+// same fields in the same order, the result scans like the first operand.
+type SetQuery[F fieldAlias] struct {
+	first      *SelectQuery[F]
+	operations []setOperation[F]
+	orderBy    []OrderTerm[F]
+	limit      int
+	offset     int
+}
+
+func newSetQuery[F fieldAlias](first *SelectQuery[F], operator string, other *SelectQuery[F]) *SetQuery[F] {
+	return &SetQuery[F]{
+		first:      first,
+		operations: []setOperation[F]{{operator: operator, query: other}},
+	}
+}
+
+func (q *SelectQuery[F]) Union(other *SelectQuery[F]) *SetQuery[F] {
+	return newSetQuery(q, "UNION", other)
+}
+func (q *SelectQuery[F]) UnionAll(other *SelectQuery[F]) *SetQuery[F] {
+	return newSetQuery(q, "UNION ALL", other)
+}
+func (q *SelectQuery[F]) Intersect(other *SelectQuery[F]) *SetQuery[F] {
+	return newSetQuery(q, "INTERSECT", other)
+}
+func (q *SelectQuery[F]) Except(other *SelectQuery[F]) *SetQuery[F] {
+	return newSetQuery(q, "EXCEPT", other)
+}
+
+func (q *SetQuery[F]) readOnly()                {}
+func (q *SetQuery[F]) mustOrmQuery()            {}
+func (q *SetQuery[F]) tableAlias() string       { return q.first.tableAlias() }
+func (q *SetQuery[F]) scanAbleFields() []string { return q.first.scanAbleFields() }
+
+func (q *SetQuery[F]) Build() (string, []any) {
+	i := 1
+	sb := sbPool.Get().(*strings.Builder)
+	sb.Reset()
+	sb.Grow(256 * (len(q.operations) + 1))
+
+	args := make([]any, 0, len(q.operations)*2)
+	q.build(sb, q.tableAlias(), &i, &args)
+	sb.WriteByte(';')
+	sql := sb.String() // копия в новую строку
+	sbPool.Put(sb)
+	return sql, args
+}
+
+func (q *SetQuery[F]) build(buf *strings.Builder, _ string, paramIndex *int, args *[]any) {
+	// operands in parentheses so each of them can keep own ORDER BY / LIMIT
+	buf.WriteByte('(')
+	q.first.build(buf, q.first.tableAlias(), paramIndex, args)
+	buf.WriteByte(')')
+	for _, op := range q.operations {
+		buf.WriteByte(' ')
+		buf.WriteString(op.operator)
+		buf.WriteString(" (")
+		op.query.build(buf, op.query.tableAlias(), paramIndex, args)
+		buf.WriteByte(')')
+	}
+
+	// result columns are unqualified here
+	if len(q.orderBy) > 0 {
+		buf.WriteString(" ORDER BY ")
+		for i, o := range q.orderBy {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			o.build(buf, "")
+		}
+	}
+	if q.limit > 0 {
+		buf.WriteString(" LIMIT ")
+		buf.WriteString(strconv.Itoa(q.limit))
+	}
+	if q.offset > 0 {
+		buf.WriteString(" OFFSET ")
+		buf.WriteString(strconv.Itoa(q.offset))
+	}
+}
+
+func (q *SetQuery[F]) Union(other *SelectQuery[F]) *SetQuery[F] {
+	q.operations = append(q.operations, setOperation[F]{operator: "UNION", query: other})
+	return q
+}
+func (q *SetQuery[F]) UnionAll(other *SelectQuery[F]) *SetQuery[F] {
+	q.operations = append(q.operations, setOperation[F]{operator: "UNION ALL", query: other})
+	return q
+}
+func (q *SetQuery[F]) Intersect(other *SelectQuery[F]) *SetQuery[F] {
+	q.operations = append(q.operations, setOperation[F]{operator: "INTERSECT", query: other})
+	return q
+}
+func (q *SetQuery[F]) Except(other *SelectQuery[F]) *SetQuery[F] {
+	q.operations = append(q.operations, setOperation[F]{operator: "EXCEPT", query: other})
+	return q
+}
+func (q *SetQuery[F]) OrderByASC(fields ...F) *SetQuery[F] {
+	for _, f := range fields {
+		q.orderBy = append(q.orderBy, Asc(f))
+	}
+	return q
+}
+func (q *SetQuery[F]) OrderByDESC(fields ...F) *SetQuery[F] {
+	for _, f := range fields {
+		q.orderBy = append(q.orderBy, Desc(f))
+	}
+	return q
+}
+func (q *SetQuery[F]) Limit(limit int) *SetQuery[F] {
+	q.limit = limit
+	return q
+}
+func (q *SetQuery[F]) Offset(offset int) *SetQuery[F] {
+	q.offset = offset
+	return q
+}
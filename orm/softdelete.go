@@ -0,0 +1,113 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ---------------------------------------------------------------------------
+// Soft delete ----------------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+var ErrNoSoftDelete = errors.New("table has no soft delete column")
+
+// deletedScope — soft deleted rows matched by selects, rows are excluded by
+// default
+type deletedScope int
+
+const (
+	excludeDeleted deletedScope = iota
+	includeDeleted
+	onlyDeleted
+)
+
+type softDeleteScope[F fieldAlias] struct {
+	column F
+	scope  deletedScope
+}
+
+// clause — nil when deleted rows are included
+func (s *softDeleteScope[F]) clause() Clause[F] {
+	switch s.scope {
+	case excludeDeleted:
+		return &FieldClause[F]{Field: s.column, Operator: "IS NULL", Right: &RawExprClause[F]{SQL: ""}}
+	case onlyDeleted:
+		return &FieldClause[F]{Field: s.column, Operator: "IS NOT NULL", Right: &RawExprClause[F]{SQL: ""}}
+	}
+	return nil
+}
+
+// withSoftDelete — deletion time column emitted by the generator, selects of
+// the table skip rows where it is set
+func (t *table[F, T]) withSoftDelete(column F) *table[F, T] {
+	t.softDelete = &softDeleteScope[F]{column: column}
+	return t
+}
+
+func (t *table[F, T]) mustSoftDelete() {
+	if t.softDelete == nil {
+		panic(fmt.Sprintf("pgx-orm: %s: %s", t.alias, ErrNoSoftDelete))
+	}
+}
+
+// SoftDelete — UPDATE setting the deletion time of not deleted rows, panics
+// when the table has no soft delete column
+func (t *table[F, T]) SoftDelete() *UpdateQuery[F] {
+	t.mustSoftDelete()
+	return t.Update().
+		Set(&valueSetterImpl[F]{field: t.softDelete.column, expr: "now()"}).
+		Where(t.softDelete.clause())
+}
+
+// Restore — UPDATE clearing the deletion time, panics when the table has no
+// soft delete column
+func (t *table[F, T]) Restore() *UpdateQuery[F] {
+	t.mustSoftDelete()
+	return t.Update().Set(&valueSetterImpl[F]{field: t.softDelete.column, expr: "NULL"})
+}
+
+// deleteQuery — SoftDelete for tables with soft delete column, DELETE otherwise
+func (t *table[F, T]) deleteQuery(clause ...Clause[F]) ormQuery {
+	if t.softDelete != nil {
+		return t.SoftDelete().Where(clause...)
+	}
+	return t.Delete().Where(clause...)
+}
+
+func (q *SelectQuery[F]) withScope(scope deletedScope) *SelectQuery[F] {
+	if q.softDelete != nil {
+		q.softDelete = &softDeleteScope[F]{column: q.softDelete.column, scope: scope}
+	}
+	return q
+}
+
+// WithDeleted includes soft deleted rows
+func (q *SelectQuery[F]) WithDeleted() *SelectQuery[F] {
+	return q.withScope(includeDeleted)
+}
+
+// OnlyDeleted matches soft deleted rows only
+func (q *SelectQuery[F]) OnlyDeleted() *SelectQuery[F] {
+	return q.withScope(onlyDeleted)
+}
+
+// whereClauses with the soft delete condition
+func (q *SelectQuery[F]) where() []Clause[F] {
+	if q.softDelete == nil {
+		return q.whereClauses
+	}
+	if clause := q.softDelete.clause(); clause != nil {
+		return append(q.whereClauses[:len(q.whereClauses):len(q.whereClauses)], clause)
+	}
+	return q.whereClauses
+}
+
+// scoped applies WithDeleted/OnlyDeleted options to a copy of select queries
+func scoped[F fieldAlias](query ormQuery, scope deletedScope) ormQuery {
+	q, ok := query.(*SelectQuery[F])
+	if !ok || scope == excludeDeleted || q.softDelete == nil {
+		return query
+	}
+	ret := *q
+	return ret.withScope(scope)
+}
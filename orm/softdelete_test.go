@@ -0,0 +1,51 @@
+package orm
+
+import (
+	"testing"
+)
+
+func TestSoftDelete(t *testing.T) {
+	tb := newTestTable("id", "deleted_at").withSoftDelete(testField("deleted_at"))
+	id := testField("id")
+	tests := []struct {
+		name  string
+		query ormQuery
+		want  string
+	}{
+		{
+			name:  "select skips deleted",
+			query: tb.SelectAll().Where(&FieldClause[fieldAlias]{Field: id, Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}}),
+			want:  "SELECT users.id, users.deleted_at FROM users AS users WHERE users.id = $1 AND users.deleted_at IS NULL ;",
+		},
+		{
+			name:  "with deleted",
+			query: tb.SelectAll().WithDeleted(),
+			want:  "SELECT users.id, users.deleted_at FROM users AS users;",
+		},
+		{
+			name:  "only deleted option",
+			query: scoped[fieldAlias](tb.SelectAll(), onlyDeleted),
+			want:  "SELECT users.id, users.deleted_at FROM users AS users WHERE users.deleted_at IS NOT NULL ;",
+		},
+		{
+			name:  "delete sets deletion time",
+			query: tb.deleteQuery(&FieldClause[fieldAlias]{Field: id, Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}}),
+			want:  "UPDATE users SET deleted_at = now() WHERE users.deleted_at IS NULL  AND users.id = $1;",
+		},
+		{
+			name:  "restore",
+			query: tb.Restore(),
+			want:  "UPDATE users SET deleted_at = NULL;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if sql, _ := tt.query.Build(); sql != tt.want {
+				t.Fatalf("soft delete SQL fail:\nwant: %s\ngot : %s", tt.want, sql)
+			}
+		})
+	}
+	if fields := tb.writeFields(); len(fields) != 1 || fields[0].String() != "id" {
+		t.Fatalf("soft delete column must not be written: %v", fields)
+	}
+}
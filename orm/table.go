@@ -3,13 +3,21 @@ package orm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"iter"
+	"slices"
+	"strings"
 )
 
 type TableI[F fieldAlias, T targeter[F]] interface {
 	AllFields() []F
 	AllFieldsExcept(field ...F) []F
 	Name() string
+	PrimaryKey() []F
+	UniqueKeys() [][]F
 	NewScanner() T
 	Select(field ...F) *SelectQuery[F]
 	Select1() *SelectQuery[F]
@@ -17,14 +25,35 @@ type TableI[F fieldAlias, T targeter[F]] interface {
 	Insert() *InsertQuery[F]
 	Update() *UpdateQuery[F]
 	Delete() *DeleteQuery[F]
+	SoftDelete() *UpdateQuery[F]
+	Restore() *UpdateQuery[F]
 	Query(ctx context.Context, db DB, query ormQuery) ([]T, error)
 	QueryRow(ctx context.Context, db DB, query ormQuery) (T, error)
+	Iter(ctx context.Context, db DB, query ormQuery) iter.Seq2[T, error]
+	QueryPage(ctx context.Context, db DB, query *SelectQuery[F], token string, size int) ([]T, string, error)
 	Execute(ctx context.Context, db DB, query ormQuery) (int64, error)
+	Exists(ctx context.Context, db DB, clause ...Clause[F]) (bool, error)
+	ParseFilter(filter string) ([]Clause[F], error)
+	ParseOrderBy(orderBy string) ([]OrderTerm[F], error)
 }
 type table[F fieldAlias, T targeter[F]] struct {
 	alias       string
 	allFields   []F // hack to set outside
-	scanFactory func() T
+	primaryKey  []F
+	uniqueKeys  [][]F
+	maskPaths   map[string][]F
+	pathFields  map[string]pathField[F]
+	softDelete  *softDeleteScope[F]
+	version     *F
+	versionName protoreflect.Name // message field of version
+	createdAt   *F
+	updatedAt   *F
+	constraints map[string]map[string][]string
+	// timestampNames — message fields of createdAt and updatedAt, empty for
+	// virtual fields
+	timestampNames [2]protoreflect.Name
+	graph          []graphSaver // relations saved with the rows of the table
+	scanFactory    func() T
 }
 
 func newTable[F fieldAlias, T targeter[F]](
@@ -39,6 +68,54 @@ func newTable[F fieldAlias, T targeter[F]](
 	}
 }
 
+// withPrimaryKey — key metadata emitted by the generator, used as default
+// conflict target of upserts
+func (t *table[F, T]) withPrimaryKey(fields ...F) *table[F, T] {
+	t.primaryKey = fields
+	return t
+}
+func (t *table[F, T]) withUnique(fields ...F) *table[F, T] {
+	t.uniqueKeys = append(t.uniqueKeys, fields)
+	return t
+}
+
+// withConstraints — table → constraint → columns mapping of the generated
+// package, so violations raised on another table (e.g. FK on delete) are
+// resolved too, see wrapError
+func (t *table[F, T]) withConstraints(constraints map[string]map[string][]string) *table[F, T] {
+	t.constraints = constraints
+	return t
+}
+
+// withMaskPaths — FieldMask path to columns mapping emitted by the generator
+func (t *table[F, T]) withMaskPaths(paths map[string][]F) *table[F, T] {
+	t.maskPaths = paths
+	return t
+}
+
+// maskFields resolves FieldMask paths to columns, "*" selects every column
+func (t *table[F, T]) maskFields(mask *fieldmaskpb.FieldMask) ([]F, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, fmt.Errorf("%w: empty paths", ErrInvalidFieldMask)
+	}
+	ret := make([]F, 0, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		if path == "*" {
+			return t.allFields, nil
+		}
+		fields, ok := t.maskPaths[path]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown path %q for %s", ErrInvalidFieldMask, path, t.alias)
+		}
+		for _, f := range fields {
+			if !slices.ContainsFunc(ret, func(r F) bool { return r.String() == f.String() }) {
+				ret = append(ret, f)
+			}
+		}
+	}
+	return ret, nil
+}
+
 func (t *table[F, T]) baseQuery(ta string, field ...F) baseQuery[F] {
 	return baseQuery[F]{
 		ta:          ta,
@@ -49,6 +126,12 @@ func (t *table[F, T]) baseQuery(ta string, field ...F) baseQuery[F] {
 func (t *table[F, T]) Name() string {
 	return t.alias
 }
+func (t *table[F, T]) PrimaryKey() []F {
+	return t.primaryKey
+}
+func (t *table[F, T]) UniqueKeys() [][]F {
+	return t.uniqueKeys
+}
 func (t *table[F, T]) AllFields() []F {
 	return t.allFields
 }
@@ -73,7 +156,8 @@ func (t *table[F, T]) NewScanner() T {
 }
 func (t *table[F, T]) Select(field ...F) *SelectQuery[F] {
 	return &SelectQuery[F]{
-		baseQuery: t.baseQuery(t.alias, field...),
+		baseQuery:  t.baseQuery(t.alias, field...),
+		softDelete: t.softDelete,
 	}
 }
 func (t *table[F, T]) Select1() *SelectQuery[F] {
@@ -98,51 +182,180 @@ func (t *table[F, T]) Insert() *InsertQuery[F] {
 	}
 }
 
-func (t *table[F, T]) QueryRow(ctx context.Context, db DB, query ormQuery) (trg T, err error) {
-	trg = t.scanFactory()
-	scanAbleFields := query.scanAbleFields()
+func (t *table[F, T]) QueryRow(ctx context.Context, db DB, query ormQuery) (T, error) {
+	if err := queryErr(query); err != nil {
+		var zero T
+		return zero, err
+	}
 	sql, args := query.Build()
-	targets := make([]any, 0, len(scanAbleFields))
-	for _, f := range scanAbleFields {
-		targets = append(targets, trg.getTarget(f)())
+	ctx, db, end := t.observe(ctx, db, OperationQueryRow, sql, len(args))
+	trg, err := t.scanRow(db.QueryRow(ctx, sql, args...), query.scanAbleFields())
+	end(rowCount(err), err)
+	return trg, t.wrapError(err)
+}
+
+// Query scans all returned rows. Queries over the parameters limit are sent
+// as several statements, wrap the call into a transaction to keep it atomic.
+func (t *table[F, T]) Query(ctx context.Context, db DB, query ormQuery) (trgs []T, err error) {
+	if err = queryErr(query); err != nil {
+		return nil, err
 	}
-	err = db.QueryRow(ctx, sql, args...).Scan(targets...)
-	if err != nil {
-		return trg, err
+	for _, part := range splitQuery(query) {
+		trgs, err = t.query(ctx, db, part, trgs)
+		if err != nil {
+			return nil, t.wrapError(err)
+		}
 	}
-	return trg, nil
+	return trgs, nil
 }
 
-func (t *table[F, T]) Query(ctx context.Context, db DB, query ormQuery) (trgs []T, err error) {
-	scanAbleFields := query.scanAbleFields()
+func (t *table[F, T]) query(ctx context.Context, db DB, query ormQuery, trgs []T) ([]T, error) {
 	sql, args := query.Build()
+	ctx, db, end := t.observe(ctx, db, OperationQuery, sql, len(args))
 	rows, err := db.Query(ctx, sql, args...)
 	if err != nil {
+		end(0, err)
+		return nil, err
+	}
+	scanned := len(trgs)
+	if trgs, err = t.scanRows(rows, query.scanAbleFields(), trgs); err != nil {
+		end(0, err)
 		return nil, err
 	}
+	end(int64(len(trgs)-scanned), nil)
+	return trgs, nil
+}
+
+// Iter scans rows one by one while the consumer ranges over it, rows are
+// closed when the loop ends or breaks. An error is yielded once as the last
+// element.
+func (t *table[F, T]) Iter(ctx context.Context, db DB, query ormQuery) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err := queryErr(query); err != nil {
+			yield(zero, err)
+			return
+		}
+		for _, part := range splitQuery(query) {
+			sql, args := part.Build()
+			ctx, db, end := t.observe(ctx, db, OperationQuery, sql, len(args))
+			rows, err := db.Query(ctx, sql, args...)
+			if err != nil {
+				end(0, err)
+				yield(zero, t.wrapError(err))
+				return
+			}
+			var scanned int64
+			var scanErr error
+			done := t.iterRows(rows, part.scanAbleFields(), func(trg T, err error) bool {
+				if err != nil {
+					scanErr = err
+				} else {
+					scanned++
+				}
+				return yield(trg, err)
+			})
+			end(scanned, scanErr)
+			if !done {
+				return
+			}
+		}
+	}
+}
+
+// iterRows yields scanned rows, false means iteration is over
+func (t *table[F, T]) iterRows(rows pgx.Rows, scanAbleFields []string, yield func(T, error) bool) bool {
 	defer rows.Close()
 	for rows.Next() {
-		trg := t.scanFactory()
-		targets := make([]any, len(scanAbleFields))
-		for i, f := range scanAbleFields {
-			targets[i] = trg.getTarget(f)()
+		trg, err := t.scanRow(rows, scanAbleFields)
+		if err != nil {
+			yield(trg, t.wrapError(err))
+			return false
 		}
-		err = rows.Scan(targets...)
+		if !yield(trg, nil) {
+			return false
+		}
+	}
+	if err := rows.Err(); err != nil {
+		var zero T
+		yield(zero, t.wrapError(err))
+		return false
+	}
+	return true
+}
+
+func (t *table[F, T]) scanRows(rows pgx.Rows, scanAbleFields []string, trgs []T) ([]T, error) {
+	defer rows.Close()
+	for rows.Next() {
+		trg, err := t.scanRow(rows, scanAbleFields)
 		if err != nil {
 			return nil, err
 		}
 		trgs = append(trgs, trg)
 	}
-	return trgs, nil
+	return trgs, rows.Err()
+}
+
+func (t *table[F, T]) scanRow(row pgx.Row, scanAbleFields []string) (T, error) {
+	trg := t.scanFactory()
+	targets := make([]any, len(scanAbleFields))
+	for i, f := range scanAbleFields {
+		targets[i] = trg.getTarget(f)()
+	}
+	return trg, row.Scan(targets...)
+}
+
+// Exists — SELECT EXISTS(SELECT 1 FROM t WHERE ...)
+func (t *table[F, T]) Exists(ctx context.Context, db DB, clause ...Clause[F]) (bool, error) {
+	return t.exists(ctx, db, t.Select1().Where(clause...))
+}
+
+func (t *table[F, T]) exists(ctx context.Context, db DB, query *SelectQuery[F]) (bool, error) {
+	sb := &strings.Builder{}
+	idx := 1
+	args := make([]any, 0, len(query.whereClauses))
+	sb.WriteString("SELECT EXISTS(")
+	query.build(sb, query.tableAlias(), &idx, &args)
+	sb.WriteString(");")
+	var exists bool
+	ctx, db, end := t.observe(ctx, db, OperationQueryRow, sb.String(), len(args))
+	err := db.QueryRow(ctx, sb.String(), args...).Scan(&exists)
+	end(rowCount(err), err)
+	return exists, t.wrapError(err)
 }
 
+// Execute returns affected rows. Queries over the parameters limit are sent
+// as several statements in one transaction (a savepoint inside a transaction);
+// a db which cannot start transactions runs them one by one.
 func (t *table[F, T]) Execute(ctx context.Context, db DB, query ormQuery) (int64, error) {
-	sql, args := query.Build()
-	tag, err := db.Exec(ctx, sql, args...)
-	if err != nil {
+	if err := queryErr(query); err != nil {
 		return 0, err
 	}
-	return tag.RowsAffected(), nil
+	parts := splitQuery(query)
+	if len(parts) == 1 {
+		return t.execute(ctx, db, parts)
+	}
+	var affected int64
+	err := runInTx(ctx, func(context.Context, SqlOpType) DB { return db }, func(ctx context.Context, db DB) (err error) {
+		affected, err = t.execute(ctx, db, parts)
+		return err
+	})
+	return affected, err
+}
+
+func (t *table[F, T]) execute(ctx context.Context, db DB, parts []ormQuery) (int64, error) {
+	var affected int64
+	for _, part := range parts {
+		sql, args := part.Build()
+		ctx, db, end := t.observe(ctx, db, OperationExec, sql, len(args))
+		tag, err := db.Exec(ctx, sql, args...)
+		end(tag.RowsAffected(), err)
+		if err != nil {
+			return 0, t.wrapError(err)
+		}
+		affected += tag.RowsAffected()
+	}
+	return affected, nil
 }
 func (t *table[F, T]) Raw(sql string, args ...any) Clause[F] {
 	return &RawExprClause[F]{sql, args}
@@ -166,14 +379,38 @@ func (t *table[F, T]) Or(clauses ...Clause[F]) Clause[F] {
 	return &OrClause[F]{clauses}
 }
 
-type copyIterator[T valuer] struct {
+func (t *table[F, T]) Window() *WindowSpec[F] {
+	return &WindowSpec[F]{}
+}
+func (t *table[F, T]) RowNumber() *WindowExpr[F] {
+	return newWindowExpr[F]("ROW_NUMBER")
+}
+func (t *table[F, T]) Rank() *WindowExpr[F] {
+	return newWindowExpr[F]("RANK")
+}
+func (t *table[F, T]) DenseRank() *WindowExpr[F] {
+	return newWindowExpr[F]("DENSE_RANK")
+}
+func (t *table[F, T]) PercentRank() *WindowExpr[F] {
+	return newWindowExpr[F]("PERCENT_RANK")
+}
+func (t *table[F, T]) CumeDist() *WindowExpr[F] {
+	return newWindowExpr[F]("CUME_DIST")
+}
+func (t *table[F, T]) Ntile(buckets int) *WindowExpr[F] {
+	return newWindowExpr[F]("NTILE", buckets)
+}
+
+type copyIterator[T any] struct {
 	rows                 []T
+	values               func(T) []any
 	skippedFirstNextCall bool
 }
 
-func newCopyIterator[T valuer](rows []T) *copyIterator[T] {
+func newCopyIterator[T any](rows []T, values func(T) []any) *copyIterator[T] {
 	return &copyIterator[T]{
-		rows: rows,
+		rows:   rows,
+		values: values,
 	}
 }
 func (r *copyIterator[T]) Err() error { return nil }
@@ -188,20 +425,23 @@ func (r *copyIterator[T]) Next() bool {
 	r.rows = r.rows[1:]
 	return len(r.rows) > 0
 }
-func (r *copyIterator[F]) Values() ([]interface{}, error) {
-	return r.rows[0].values(), nil
+func (r *copyIterator[T]) Values() ([]interface{}, error) {
+	return r.values(r.rows[0]), nil
 }
 
 func (t *table[F, T]) CopyFrom(ctx context.Context, db DB, values []T, fields ...F) (int64, error) {
 	if len(fields) == 0 {
 		return 0, errors.New("pgx-orm: fields is empty")
 	}
-	if len(fields) > 0 {
-		fields = t.allFields
-	}
 	fieldsStrings := make([]string, len(fields))
 	for i, f := range fields {
 		fieldsStrings[i] = f.String()
 	}
-	return db.CopyFrom(ctx, pgx.Identifier{t.alias}, fieldsStrings, newCopyIterator[T](values))
+	sql := "COPY " + t.alias + " (" + strings.Join(fieldsStrings, ", ") + ") FROM STDIN"
+	ctx, db, end := t.observe(ctx, db, OperationCopy, sql, len(values)*len(fields))
+	copied, err := db.CopyFrom(ctx, pgx.Identifier{t.alias}, fieldsStrings, newCopyIterator(values, func(row T) []any {
+		return GetFieldsValues(row, fields...)
+	}))
+	end(copied, err)
+	return copied, t.wrapError(err)
 }
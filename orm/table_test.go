@@ -1,5 +1,15 @@
 package orm
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"testing"
+)
+
 //
 //type StField interface {
 //	fieldAlias
@@ -222,3 +232,122 @@ package orm
 ////	}
 ////	t.Logf("DATA: %v", someData)
 ////}
+
+type testScanner map[string]any
+
+func (s testScanner) values() []any { return nil }
+func (s testScanner) getTarget(field string) func() any {
+	return func() any {
+		target := new(any)
+		s[field] = target
+		return target
+	}
+}
+func (s testScanner) getSetter(field fieldAlias) func() ValueSetter[fieldAlias] {
+	return func() ValueSetter[fieldAlias] { return NewValueSetter[fieldAlias](field, s[field.String()]) }
+}
+func (s testScanner) getValue(field fieldAlias) func() any {
+	return func() any {
+		if scanned, ok := s[field.String()].(*any); ok {
+			return *scanned
+		}
+		return s[field.String()]
+	}
+}
+
+func newTestTable(fields ...string) *table[fieldAlias, testScanner] {
+	aliases := make([]fieldAlias, 0, len(fields))
+	for _, f := range fields {
+		aliases = append(aliases, testField(f))
+	}
+	return newTable[fieldAlias, testScanner]("users", func() testScanner { return testScanner{} }, aliases...)
+}
+
+func TestMaskFields(t *testing.T) {
+	tb := newTestTable("id", "name", "city", "street").withMaskPaths(map[string][]fieldAlias{
+		"name":           {testField("name")},
+		"address":        {testField("city"), testField("street")},
+		"address.city":   {testField("city")},
+		"address.street": {testField("street")},
+	})
+	fields, err := tb.maskFields(&fieldmaskpb.FieldMask{Paths: []string{"name", "address.city", "address"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(fields); got != "[name city street]" {
+		t.Fatalf("mask fields mismatch: %s", got)
+	}
+	if _, err = tb.maskFields(&fieldmaskpb.FieldMask{Paths: []string{"address.zip"}}); !errors.Is(err, ErrInvalidFieldMask) {
+		t.Fatalf("expected ErrInvalidFieldMask, got %v", err)
+	}
+	if _, err = tb.maskFields(nil); !errors.Is(err, ErrInvalidFieldMask) {
+		t.Fatalf("expected ErrInvalidFieldMask for empty mask, got %v", err)
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	tb := newTestTable("id", "email").withConstraints(map[string]map[string][]string{
+		"users": {"users_email_key": {"email"}},
+		"posts": {"posts_user_id_fkey": {"user_id"}},
+	})
+	err := tb.wrapError(pgx.ErrNoRows)
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("not found mismatch: %v", err)
+	}
+	err = tb.wrapError(&pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"})
+	var unique *ErrUniqueViolation
+	if !errors.As(err, &unique) || fmt.Sprint(unique.Columns) != "[email]" || unique.Table != "users" {
+		t.Fatalf("unique violation mismatch: %#v", err)
+	}
+	err = tb.wrapError(&pgconn.PgError{Code: "23503", TableName: "posts", ConstraintName: "posts_user_id_fkey"})
+	var fk *ErrForeignKeyViolation
+	if !errors.As(err, &fk) || fk.Table != "posts" || fmt.Sprint(fk.Columns) != "[user_id]" {
+		t.Fatalf("fk violation mismatch: %#v", err)
+	}
+}
+
+type testRows struct {
+	pgx.Rows
+	data   [][]any
+	row    int
+	closed bool
+}
+
+func (r *testRows) Next() bool { r.row++; return r.row <= len(r.data) }
+func (r *testRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		*d.(*any) = r.data[r.row-1][i]
+	}
+	return nil
+}
+func (r *testRows) Err() error { return nil }
+func (r *testRows) Close()     { r.closed = true }
+
+type testQueryDB struct {
+	DB
+	rows *testRows
+	sql  string
+	args []any
+}
+
+func (d *testQueryDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	d.sql, d.args = sql, args
+	return d.rows, nil
+}
+
+func TestIterBreak(t *testing.T) {
+	tb := newTestTable("id")
+	db := &testQueryDB{rows: &testRows{data: [][]any{{1}, {2}, {3}, {4}, {5}}}}
+	read := 0
+	for _, err := range tb.Iter(context.Background(), db, tb.SelectAll()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read++; read == 2 {
+			break
+		}
+	}
+	if read != 2 || !db.rows.closed {
+		t.Fatalf("rows must be closed on break: read=%d closed=%v", read, db.rows.closed)
+	}
+}
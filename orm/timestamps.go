@@ -0,0 +1,96 @@
+package orm
+
+import (
+	"context"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ---------------------------------------------------------------------------
+// Managed columns ------------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// withTimestamps — created_at/updated_at columns emitted by the generator
+// and their message fields, inserts leave them to defaults and updates set
+// updated_at to now()
+func (t *table[F, T]) withTimestamps(createdAt, updatedAt F, createdName, updatedName protoreflect.Name) *table[F, T] {
+	t.createdAt, t.updatedAt = &createdAt, &updatedAt
+	t.timestampNames = [2]protoreflect.Name{createdName, updatedName}
+	return t
+}
+
+// execTimestamps runs an insert or update returning the timestamps and scans
+// them into entity, so it holds the values set by the database. Returns the
+// number of written rows.
+func (t *table[F, T]) execTimestamps(ctx context.Context, db DB, query ormQuery, entity T) (int64, error) {
+	if err := queryErr(query); err != nil {
+		return 0, err
+	}
+	sql, args := query.Build()
+	ctx, db, end := t.observe(ctx, db, OperationQuery, sql, len(args))
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		end(0, err)
+		return 0, t.wrapError(err)
+	}
+	defer rows.Close()
+	var written int64
+	for rows.Next() {
+		err = rows.Scan(entity.getTarget((*t.createdAt).String())(), entity.getTarget((*t.updatedAt).String())())
+		if err != nil {
+			break
+		}
+		written++
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	end(written, err)
+	return written, t.wrapError(err)
+}
+
+// copyTimestamps sets the timestamp fields of the message dst from src, the
+// upcast of the scanner filled by execTimestamps
+func (t *table[F, T]) copyTimestamps(dst, src protoreflect.Message) {
+	for _, name := range t.timestampNames {
+		if fd := dst.Descriptor().Fields().ByName(name); name != "" && fd != nil {
+			dst.Set(fd, src.Get(fd))
+		}
+	}
+}
+
+// managedFields — soft delete, version and timestamp columns, written by the
+// orm only
+func (t *table[F, T]) managedFields() []F {
+	ret := make([]F, 0, 4)
+	if t.softDelete != nil {
+		ret = append(ret, t.softDelete.column)
+	}
+	for _, f := range []*F{t.version, t.createdAt, t.updatedAt} {
+		if f != nil {
+			ret = append(ret, *f)
+		}
+	}
+	return ret
+}
+
+// writeFields — columns written by inserts and updates of repositories
+func (t *table[F, T]) writeFields() []F {
+	managed := t.managedFields()
+	if len(managed) == 0 {
+		return t.allFields
+	}
+	return exceptFields(t.allFields, managed)
+}
+
+// managedUpdates — setters added to updates and upserts of repositories:
+// version increment and updated_at = now()
+func (t *table[F, T]) managedUpdates() []ValueSetter[F] {
+	ret := make([]ValueSetter[F], 0, 2)
+	if t.version != nil {
+		ret = append(ret, &valueSetterImpl[F]{field: *t.version, expr: t.alias + "." + (*t.version).String() + " + 1"})
+	}
+	if t.updatedAt != nil {
+		ret = append(ret, &valueSetterImpl[F]{field: *t.updatedAt, expr: "now()"})
+	}
+	return ret
+}
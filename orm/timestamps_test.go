@@ -0,0 +1,63 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTimestamps(t *testing.T) {
+	tb := newTestTable("id", "name", "created_at", "updated_at").
+		withPrimaryKey(testField("id")).
+		withTimestamps(testField("created_at"), testField("updated_at"), "", "")
+	repo := newGenericScannerRepository(tb, nil)
+	entity := testScanner{"id": 1, "name": "a", "created_at": nil, "updated_at": nil}
+	clause := &FieldClause[fieldAlias]{Field: testField("id"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}}
+	tests := []struct {
+		name  string
+		query ormQuery
+		want  string
+	}{
+		{
+			name:  "insert leaves defaults",
+			query: repo.insertQuery(repo.opts(), entity).ReturningAll(),
+			want:  "INSERT INTO users (id, name) VALUES ($1, $2) RETURNING id, name, created_at, updated_at;",
+		},
+		{
+			name:  "update sets updated_at",
+			query: repo.updateQuery(repo.opts(), entity, clause),
+			want:  "UPDATE users SET id = $1, name = $2, updated_at = now() WHERE users.id = $3;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if sql, _ := tt.query.Build(); sql != tt.want {
+				t.Fatalf("timestamps SQL fail:\nwant: %s\ngot : %s", tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestTimestampsReturned(t *testing.T) {
+	tb := newTestTable("id", "created_at", "updated_at").
+		withPrimaryKey(testField("id")).
+		withTimestamps(testField("created_at"), testField("updated_at"), "", "value")
+	db := &testQueryDB{rows: &testRows{data: [][]any{{"created", "updated"}}}}
+	repo := newGenericRepository(
+		newGenericScannerRepository(tb, NewDbGetter(db)),
+		func(v *wrapperspb.StringValue) testScanner { return testScanner{"id": 1, "updated_at": v.GetValue()} },
+		func(s testScanner) *wrapperspb.StringValue {
+			return wrapperspb.String(fmt.Sprint(s.getValue(testField("updated_at"))()))
+		},
+	)
+	entity := wrapperspb.String("")
+	if err := repo.Insert(context.Background(), entity); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(db.sql, "RETURNING created_at, updated_at;") || entity.GetValue() != "updated" {
+		t.Fatalf("timestamps are not returned: %s %q", db.sql, entity.GetValue())
+	}
+}
@@ -0,0 +1,125 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Transactions ---------------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// PostgreSQL SQLSTATE codes worth retrying the whole transaction
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// TxStarter is implemented by *pgxpool.Pool and *pgx.Conn
+type TxStarter interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// TxOptions of the outermost transaction, nested RunInTx calls use savepoints
+// and ignore them
+type TxOptions struct {
+	IsoLevel       pgx.TxIsoLevel
+	AccessMode     pgx.TxAccessMode
+	DeferrableMode pgx.TxDeferrableMode
+	// MaxRetries on serialization failure or deadlock, 0 disables retries
+	MaxRetries int
+}
+
+type txKey struct{}
+
+// TxFromContext returns transaction started by RunInTx
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// NewDbGetter returns DbGetter which uses transaction from the context and
+// falls back to db
+func NewDbGetter(db DB) DbGetter {
+	return func(ctx context.Context, _ SqlOpType) DB {
+		if tx, ok := TxFromContext(ctx); ok {
+			return tx
+		}
+		return db
+	}
+}
+
+// RunInTx runs fn in a transaction stored in ctx, repositories built with
+// NewDbGetter pick it up. Nested calls run in a savepoint of the outer
+// transaction; the outermost call retries fn up to TxOptions.MaxRetries times
+// on serialization failure and deadlock, so fn must then be safe to run again.
+func RunInTx(ctx context.Context, db TxStarter, opts TxOptions, fn func(ctx context.Context) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return inTx(ctx, func(ctx context.Context) (pgx.Tx, error) { return tx.Begin(ctx) }, fn)
+	}
+	begin := func(ctx context.Context) (pgx.Tx, error) {
+		return db.BeginTx(ctx, pgx.TxOptions{
+			IsoLevel:       opts.IsoLevel,
+			AccessMode:     opts.AccessMode,
+			DeferrableMode: opts.DeferrableMode,
+		})
+	}
+	for attempt := 0; ; attempt++ {
+		err := inTx(ctx, begin, fn)
+		if err == nil || attempt >= opts.MaxRetries || !isRetryableTxError(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(time.Duration(attempt+1) * 10 * time.Millisecond):
+		}
+	}
+}
+
+// runInTx runs fn in a transaction over the mutation db of dbGetter, a nested
+// one when db is a transaction. db which cannot start transactions is used as is.
+// fn is run once, it is never retried.
+func runInTx(ctx context.Context, dbGetter DbGetter, fn func(ctx context.Context, db DB) error) error {
+	db, observer := unobserved(dbGetter(ctx, SqlMutation))
+	inCtx := func(ctx context.Context) error {
+		tx, _ := TxFromContext(ctx)
+		return fn(ctx, withObserver(tx, observer))
+	}
+	switch db := db.(type) {
+	case TxStarter:
+		return RunInTx(ctx, db, TxOptions{}, inCtx)
+	case pgx.Tx:
+		return inTx(ctx, db.Begin, inCtx)
+	default:
+		return fn(ctx, withObserver(db, observer))
+	}
+}
+
+func inTx(
+	ctx context.Context,
+	begin func(ctx context.Context) (pgx.Tx, error),
+	fn func(ctx context.Context) error,
+) error {
+	tx, err := begin(ctx)
+	if err != nil {
+		return err
+	}
+	// no-op after commit, rolls back on error and panic
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+}
@@ -0,0 +1,75 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"testing"
+)
+
+type testTx struct {
+	pgx.Tx
+	log   *[]string
+	depth int
+}
+
+func (t *testTx) Begin(context.Context) (pgx.Tx, error) {
+	*t.log = append(*t.log, "savepoint")
+	return &testTx{log: t.log, depth: t.depth + 1}, nil
+}
+func (t *testTx) Commit(context.Context) error {
+	*t.log = append(*t.log, "commit")
+	return nil
+}
+func (t *testTx) Rollback(context.Context) error {
+	*t.log = append(*t.log, "rollback")
+	return nil
+}
+
+type testTxStarter struct{ log []string }
+
+func (s *testTxStarter) BeginTx(_ context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	s.log = append(s.log, "begin "+string(opts.IsoLevel))
+	return &testTx{log: &s.log}, nil
+}
+
+func TestRunInTxRetryAndSavepoint(t *testing.T) {
+	db := &testTxStarter{}
+	attempts := 0
+	err := RunInTx(context.Background(), db, TxOptions{IsoLevel: pgx.Serializable, MaxRetries: 3}, func(ctx context.Context) error {
+		attempts++
+		outer, _ := TxFromContext(ctx)
+		err := RunInTx(ctx, db, TxOptions{}, func(ctx context.Context) error {
+			if inner, _ := TxFromContext(ctx); inner == outer {
+				t.Fatal("nested call must use a savepoint")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &pgconn.PgError{Code: pgSerializationFailure}
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("retry fail: attempts=%d err=%v", attempts, err)
+	}
+	want := "[begin serializable savepoint commit rollback rollback begin serializable savepoint commit rollback commit rollback]"
+	if got := fmt.Sprint(db.log); got != want {
+		t.Fatalf("tx log mismatch:\nwant: %s\ngot : %s", want, got)
+	}
+}
+
+func TestRunInTxNoRetry(t *testing.T) {
+	attempts := 0
+	err := RunInTx(context.Background(), &testTxStarter{}, TxOptions{}, func(context.Context) error {
+		attempts++
+		return &pgconn.PgError{Code: pgDeadlockDetected}
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("zero MaxRetries must not retry: attempts=%d err=%v", attempts, err)
+	}
+}
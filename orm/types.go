@@ -39,6 +39,8 @@ type fieldAliasImpl string
 func (f fieldAliasImpl) IsCount() bool  { return false }
 func (f fieldAliasImpl) String() string { return string(f) }
 
+// sqlBuilder renders a fragment that can be embedded into another statement;
+// only the top-level Build of a query terminates it with ';'
 type sqlBuilder interface {
 	build(buf *strings.Builder, ta string, paramIndex *int, args *[]any)
 }
@@ -62,6 +64,22 @@ type ormQuery interface {
 	Build() (string, []any)
 }
 
+// maxQueryParams — PostgreSQL limit of bind parameters in one statement
+const maxQueryParams = 65535
+
+// chunkedQuery is implemented by queries which may be executed as several
+// statements, see splitQuery
+type chunkedQuery interface {
+	chunks() []ormQuery
+}
+
+func splitQuery(query ormQuery) []ormQuery {
+	if c, ok := query.(chunkedQuery); ok {
+		return c.chunks()
+	}
+	return []ormQuery{query}
+}
+
 type baseQuery[F fieldAlias] struct {
 	ta          string
 	usingFields []F
@@ -78,7 +96,7 @@ func (q *baseQuery[F]) scanAbleFields() []string {
 	return mp
 }
 func (q *baseQuery[F]) buildReturning(sb *strings.Builder) {
-	if len(q.usingFields) == len(q.allFields) {
+	if len(q.usingFields) > 0 && len(q.usingFields) == len(q.allFields) {
 		sb.WriteString(" RETURNING ")
 		for i, field := range q.usingFields {
 			if i > 0 {
@@ -147,8 +165,25 @@ var (
 	ErrEmptyFields = errors.New("empty fields")
 	ErrEmptyModel  = errors.New("empty model")
 	ErrEmptyQuery  = errors.New("empty query")
+
+	ErrInvalidFieldMask = errors.New("invalid field mask")
+	ErrRowsMismatch     = errors.New("rows have different columns")
+	ErrInvalidConflict  = errors.New("invalid conflict target")
 )
 
+// invalidQuery is implemented by builders recording misuse, the query fails
+// before it is sent
+type invalidQuery interface {
+	Err() error
+}
+
+func queryErr(query ormQuery) error {
+	if q, ok := query.(invalidQuery); ok {
+		return q.Err()
+	}
+	return nil
+}
+
 type TypeCaster[A, B any] func(A) B
 type SqlOpType string
 
@@ -22,6 +22,7 @@ func (q *UpdateQuery[F]) Build() (string, []any) {
 
 	args := make([]any, 0, len(q.setAssigns)+len(q.whereClauses)*2)
 	q.build(sb, q.tableAlias(), &i, &args)
+	sb.WriteByte(';')
 	sql := sb.String() // копия в новую строку
 	sbPool.Put(sb)
 	return sql, args
@@ -49,8 +50,6 @@ func (q *UpdateQuery[F]) build(buf *strings.Builder, ta string, paramIndex *int,
 	}
 
 	q.buildReturning(buf)
-
-	buf.WriteByte(';')
 }
 
 func (q *UpdateQuery[F]) Where(clause ...Clause[F]) *UpdateQuery[F] {
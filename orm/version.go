@@ -0,0 +1,78 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"reflect"
+)
+
+// ---------------------------------------------------------------------------
+// Optimistic locking ---------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+// ErrStaleObject is returned by updates of versioned tables when no row has
+// the expected version, errors.Is matches ErrNotFound too
+var ErrStaleObject = errors.New("stale object")
+
+// withVersion — version column emitted by the generator and its message
+// field, updates check and increment it
+func (t *table[F, T]) withVersion(column F, field protoreflect.Name) *table[F, T] {
+	t.version, t.versionName = &column, field
+	return t
+}
+
+// versionClause — version = expected, expected defaults to the version of the
+// entity
+func (t *table[F, T]) versionClause(entity T, expected any) Clause[F] {
+	if expected == nil {
+		expected = entity.getValue(*t.version)()
+	}
+	return &FieldClause[F]{Field: *t.version, Operator: "=", Right: &ParamExprClause[F]{Value: expected}}
+}
+
+// advanceVersion sets the version field of the updated entity to the value
+// stored by the update, so it may be updated again
+func (t *table[F, T]) advanceVersion(entity protoreflect.Message, model T, expected any) {
+	if t.version == nil {
+		return
+	}
+	if expected == nil {
+		expected = model.getValue(*t.version)()
+	}
+	fd := entity.Descriptor().Fields().ByName(t.versionName)
+	version := reflect.ValueOf(relationKey(expected))
+	if fd == nil || !version.CanInt() {
+		return
+	}
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		entity.Set(fd, protoreflect.ValueOfInt32(int32(version.Int()+1)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		entity.Set(fd, protoreflect.ValueOfInt64(version.Int()+1))
+	}
+}
+
+// staleError — ErrStaleObject instead of ErrNotFound of versioned tables
+func (t *table[F, T]) staleError(err error) error {
+	if t.version != nil && errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%s %w: %w", t.alias, ErrStaleObject, err)
+	}
+	return err
+}
+
+// staleAffected — staleError of an update that affected no rows
+func (t *table[F, T]) staleAffected(affected int64, err error) error {
+	if t.version != nil && err == nil && affected == 0 {
+		return t.staleError(ErrNotFound)
+	}
+	return err
+}
+
+// staleBatchResult applies stale on Result call
+func staleBatchResult[R any](src *BatchResult[R], stale func(R, error) error) *BatchResult[R] {
+	return &BatchResult[R]{get: func() (R, error) {
+		value, err := src.Result()
+		return value, stale(value, err)
+	}}
+}
@@ -0,0 +1,75 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"testing"
+)
+
+func TestVersionedUpdate(t *testing.T) {
+	tb := newTestTable("id", "name", "version").withPrimaryKey(testField("id")).withVersion(testField("version"), "value")
+	db := &testExecDB{}
+	repo := newGenericScannerRepository(tb, NewDbGetter(db))
+	entity := testScanner{"id": 1, "name": "a", "version": int64(3)}
+	clause := &FieldClause[fieldAlias]{Field: testField("id"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}}
+
+	err := repo.Update(context.Background(), entity, clause)
+	if !errors.Is(err, ErrStaleObject) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update of no rows must be stale: %v", err)
+	}
+	want := "UPDATE users SET id = $1, name = $2, version = users.version + 1 WHERE users.id = $3 AND users.version = $4;"
+	if len(db.sql) != 1 || db.sql[0] != want {
+		t.Fatalf("versioned update SQL fail:\nwant: %s\ngot : %v", want, db.sql)
+	}
+
+	query := repo.updateQuery(repo.opts(WithScannerExpectedVersion[fieldAlias, testScanner](int64(7))), entity, clause)
+	if _, args := query.Build(); args[len(args)-1] != int64(7) {
+		t.Fatalf("expected version is not checked: %v", args)
+	}
+	upsert, err := repo.upsertQuery(repo.opts(), entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name, version = users.version + 1;"
+	if sql, _ := upsert.Build(); sql != want {
+		t.Fatalf("versioned upsert SQL fail:\nwant: %s\ngot : %s", want, sql)
+	}
+}
+
+type testAffectedDB struct {
+	DB
+	args [][]any
+}
+
+func (d *testAffectedDB) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	d.args = append(d.args, args)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func TestVersionedRepositoryUpdate(t *testing.T) {
+	tb := newTestTable("id", "version").withPrimaryKey(testField("id")).withVersion(testField("version"), "value")
+	db := &testAffectedDB{}
+	repo := newGenericRepository(
+		newGenericScannerRepository(tb, NewDbGetter(db)),
+		func(v *wrapperspb.Int64Value) testScanner { return testScanner{"id": 1, "version": v.GetValue()} },
+		func(s testScanner) *wrapperspb.Int64Value { return wrapperspb.Int64(s["version"].(int64)) },
+	)
+	entity := wrapperspb.Int64(3)
+	clause := &FieldClause[fieldAlias]{Field: testField("id"), Operator: "=", Right: &ParamExprClause[fieldAlias]{Value: 1}}
+
+	for _, expected := range []int64{3, 4} {
+		if err := repo.Update(context.Background(), entity, clause); err != nil {
+			t.Fatal(err)
+		}
+		args := db.args[len(db.args)-1]
+		if args[len(args)-1] != expected || entity.GetValue() != expected+1 {
+			t.Fatalf("version is not advanced: checked %v, entity %d", args[len(args)-1], entity.GetValue())
+		}
+	}
+	err := repo.Update(context.Background(), entity, clause, WithExpectedVersion[fieldAlias, testScanner, *wrapperspb.Int64Value](int64(9)))
+	if err != nil || entity.GetValue() != 10 {
+		t.Fatalf("expected version is not advanced: %v %d", err, entity.GetValue())
+	}
+}
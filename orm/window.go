@@ -0,0 +1,233 @@
+package orm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// WINDOW expressions ---------------------------------------------------------
+// ---------------------------------------------------------------------------
+
+type NullsOrder uint8
+
+const (
+	NullsDefault NullsOrder = iota // NULLS LAST for ASC, NULLS FIRST for DESC
+	NullsFirst
+	NullsLast
+)
+
+// OrderTerm — one ORDER BY item, see column Asc/Desc
+type OrderTerm[F fieldAlias] struct {
+	Field F
+	Desc  bool
+	Nulls NullsOrder
+}
+
+func Asc[F fieldAlias](field F) OrderTerm[F] {
+	return OrderTerm[F]{Field: field}
+}
+func Desc[F fieldAlias](field F) OrderTerm[F] {
+	return OrderTerm[F]{Field: field, Desc: true}
+}
+
+func (o OrderTerm[F]) WithNulls(nulls NullsOrder) OrderTerm[F] {
+	o.Nulls = nulls
+	return o
+}
+
+// build renders the term, ta is empty for unqualified columns
+func (o OrderTerm[F]) build(buf *strings.Builder, ta string) {
+	if ta != "" {
+		buf.WriteString(ta)
+		buf.WriteByte('.')
+	}
+	buf.WriteString(o.Field.String())
+	if o.Desc {
+		buf.WriteString(" DESC")
+	} else {
+		buf.WriteString(" ASC")
+	}
+	switch o.Nulls {
+	case NullsFirst:
+		buf.WriteString(" NULLS FIRST")
+	case NullsLast:
+		buf.WriteString(" NULLS LAST")
+	}
+}
+
+// WindowSpec — PARTITION BY / ORDER BY part of OVER (...) or of a named WINDOW
+type WindowSpec[F fieldAlias] struct {
+	partitionBy []F
+	orderBy     []OrderTerm[F]
+}
+
+func (w *WindowSpec[F]) PartitionBy(fields ...F) *WindowSpec[F] {
+	w.partitionBy = append(w.partitionBy, fields...)
+	return w
+}
+func (w *WindowSpec[F]) OrderByASC(fields ...F) *WindowSpec[F] {
+	for _, f := range fields {
+		w.orderBy = append(w.orderBy, Asc(f))
+	}
+	return w
+}
+func (w *WindowSpec[F]) OrderByDESC(fields ...F) *WindowSpec[F] {
+	for _, f := range fields {
+		w.orderBy = append(w.orderBy, Desc(f))
+	}
+	return w
+}
+
+func (w *WindowSpec[F]) build(buf *strings.Builder, ta string, _ *int, _ *[]any) {
+	if len(w.partitionBy) > 0 {
+		buf.WriteString("PARTITION BY ")
+		for i, f := range w.partitionBy {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(ta)
+			buf.WriteByte('.')
+			buf.WriteString(f.String())
+		}
+	}
+	if len(w.orderBy) > 0 {
+		if len(w.partitionBy) > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString("ORDER BY ")
+		for i, o := range w.orderBy {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			o.build(buf, ta)
+		}
+	}
+}
+
+type namedWindow[F fieldAlias] struct {
+	name string
+	spec *WindowSpec[F]
+}
+
+// WindowExpr — FN(...) OVER (...) AS alias, see SelectQuery.WindowFields.
+// The alias is what outer queries see, so wrap the select with
+// SelectQuery.FromSubQuery and filter on Column.
+type WindowExpr[F fieldAlias] struct {
+	fn       string
+	field    F
+	hasField bool
+	params   []int
+	spec     WindowSpec[F]
+	window   string
+	alias    string
+}
+
+func newWindowExpr[F fieldAlias](fn string, params ...int) *WindowExpr[F] {
+	return &WindowExpr[F]{fn: fn, params: params, alias: strings.ToLower(fn)}
+}
+func newFieldWindowExpr[F fieldAlias](fn string, field F, params ...int) *WindowExpr[F] {
+	w := newWindowExpr[F](fn, params...)
+	w.field = field
+	w.hasField = true
+	return w
+}
+
+func (w *WindowExpr[F]) PartitionBy(fields ...F) *WindowExpr[F] {
+	w.spec.PartitionBy(fields...)
+	return w
+}
+func (w *WindowExpr[F]) OrderByASC(fields ...F) *WindowExpr[F] {
+	w.spec.OrderByASC(fields...)
+	return w
+}
+func (w *WindowExpr[F]) OrderByDESC(fields ...F) *WindowExpr[F] {
+	w.spec.OrderByDESC(fields...)
+	return w
+}
+
+// Over — use window declared by SelectQuery.Window instead of inline spec
+func (w *WindowExpr[F]) Over(name string) *WindowExpr[F] {
+	w.window = name
+	return w
+}
+func (w *WindowExpr[F]) As(alias string) *WindowExpr[F] {
+	w.alias = alias
+	return w
+}
+
+func (w *WindowExpr[F]) buildSelect(buf *strings.Builder, ta string, paramIndex *int, args *[]any) {
+	buf.WriteString(w.fn)
+	buf.WriteByte('(')
+	if w.hasField {
+		buf.WriteString(ta)
+		buf.WriteByte('.')
+		buf.WriteString(w.field.String())
+	}
+	for i, p := range w.params {
+		if i > 0 || w.hasField {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.Itoa(p))
+	}
+	buf.WriteString(") OVER ")
+	if w.window != "" {
+		buf.WriteString(w.window)
+	} else {
+		buf.WriteByte('(')
+		w.spec.build(buf, ta, paramIndex, args)
+		buf.WriteByte(')')
+	}
+	buf.WriteString(" AS ")
+	buf.WriteString(w.alias)
+}
+
+// Column — reference to the result of the expression in an outer query
+func (w *WindowExpr[F]) Column() WindowColumn[F] {
+	return WindowColumn[F]{alias: w.alias}
+}
+
+// WindowColumn — window expression result seen by the query wrapping its
+// select, e.g. Posts.Select().FromSubQuery(inner).Where(rn.Column().Lte(3))
+type WindowColumn[F fieldAlias] struct {
+	alias string
+}
+
+func (c WindowColumn[F]) String() string { return c.alias }
+
+func (c WindowColumn[F]) Eq(val any) Clause[F] {
+	return &WindowClause[F]{Column: c, Operator: "=", Right: &ParamExprClause[F]{Value: val}}
+}
+func (c WindowColumn[F]) Neq(val any) Clause[F] {
+	return &WindowClause[F]{Column: c, Operator: "!=", Right: &ParamExprClause[F]{Value: val}}
+}
+func (c WindowColumn[F]) Gt(val any) Clause[F] {
+	return &WindowClause[F]{Column: c, Operator: ">", Right: &ParamExprClause[F]{Value: val}}
+}
+func (c WindowColumn[F]) Gte(val any) Clause[F] {
+	return &WindowClause[F]{Column: c, Operator: ">=", Right: &ParamExprClause[F]{Value: val}}
+}
+func (c WindowColumn[F]) Lt(val any) Clause[F] {
+	return &WindowClause[F]{Column: c, Operator: "<", Right: &ParamExprClause[F]{Value: val}}
+}
+func (c WindowColumn[F]) Lte(val any) Clause[F] {
+	return &WindowClause[F]{Column: c, Operator: "<=", Right: &ParamExprClause[F]{Value: val}}
+}
+
+// WindowClause — FieldClause on a window expression result
+type WindowClause[F fieldAlias] struct {
+	Column   WindowColumn[F]
+	Operator string
+	Right    sqlBuilder
+}
+
+func (c *WindowClause[F]) mustClauseAlias(F) {}
+func (c *WindowClause[F]) build(buf *strings.Builder, ta string, paramIndex *int, args *[]any) {
+	buf.WriteString(ta)
+	buf.WriteByte('.')
+	buf.WriteString(c.Column.alias)
+	buf.WriteByte(' ')
+	buf.WriteString(c.Operator)
+	buf.WriteByte(' ')
+	c.Right.build(buf, ta, paramIndex, args)
+}
@@ -189,7 +189,109 @@ func (x ParsedField_ProtoKind) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ParsedField_ProtoKind.Descriptor instead.
 func (ParsedField_ProtoKind) EnumDescriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{7, 0}
+	return file_pgx_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type SqlSoftDelete struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// nullable TIMESTAMPTZ column with the deletion time, added as a virtual
+	// field when the message has none
+	Column        string `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SqlSoftDelete) Reset() {
+	*x = SqlSoftDelete{}
+	mi := &file_pgx_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SqlSoftDelete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SqlSoftDelete) ProtoMessage() {}
+
+func (x *SqlSoftDelete) ProtoReflect() protoreflect.Message {
+	mi := &file_pgx_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SqlSoftDelete.ProtoReflect.Descriptor instead.
+func (*SqlSoftDelete) Descriptor() ([]byte, []int) {
+	return file_pgx_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SqlSoftDelete) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+// TIMESTAMPTZ NOT NULL DEFAULT NOW() columns maintained by repositories:
+// inserts leave them to defaults, updates set updated_at to now(). Columns
+// absent from the message are added as virtual fields, empty names default to
+// created_at and updated_at
+type SqlTimestamps struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreatedAt     string                 `protobuf:"bytes,1,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,2,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SqlTimestamps) Reset() {
+	*x = SqlTimestamps{}
+	mi := &file_pgx_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SqlTimestamps) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SqlTimestamps) ProtoMessage() {}
+
+func (x *SqlTimestamps) ProtoReflect() protoreflect.Message {
+	mi := &file_pgx_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SqlTimestamps.ProtoReflect.Descriptor instead.
+func (*SqlTimestamps) Descriptor() ([]byte, []int) {
+	return file_pgx_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SqlTimestamps) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SqlTimestamps) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
 }
 
 type SqlTable struct {
@@ -198,13 +300,17 @@ type SqlTable struct {
 	TableName     *string                `protobuf:"bytes,2,opt,name=table_name,json=tableName,proto3,oneof" json:"table_name,omitempty"`
 	VirtualFields []*SqlVirtualField     `protobuf:"bytes,4,rep,name=virtual_fields,json=virtualFields,proto3" json:"virtual_fields,omitempty"`
 	Constraints   []string               `protobuf:"bytes,5,rep,name=constraints,proto3" json:"constraints,omitempty"`
+	SoftDelete    *SqlSoftDelete         `protobuf:"bytes,6,opt,name=soft_delete,json=softDelete,proto3" json:"soft_delete,omitempty"`
+	// integer column of a message field checked and incremented by updates
+	VersionColumn string         `protobuf:"bytes,7,opt,name=version_column,json=versionColumn,proto3" json:"version_column,omitempty"`
+	Timestamps    *SqlTimestamps `protobuf:"bytes,8,opt,name=timestamps,proto3" json:"timestamps,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SqlTable) Reset() {
 	*x = SqlTable{}
-	mi := &file_pgx_proto_msgTypes[0]
+	mi := &file_pgx_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -216,7 +322,7 @@ func (x *SqlTable) String() string {
 func (*SqlTable) ProtoMessage() {}
 
 func (x *SqlTable) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[0]
+	mi := &file_pgx_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -229,7 +335,7 @@ func (x *SqlTable) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlTable.ProtoReflect.Descriptor instead.
 func (*SqlTable) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{0}
+	return file_pgx_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *SqlTable) GetGenerate() bool {
@@ -260,6 +366,27 @@ func (x *SqlTable) GetConstraints() []string {
 	return nil
 }
 
+func (x *SqlTable) GetSoftDelete() *SqlSoftDelete {
+	if x != nil {
+		return x.SoftDelete
+	}
+	return nil
+}
+
+func (x *SqlTable) GetVersionColumn() string {
+	if x != nil {
+		return x.VersionColumn
+	}
+	return ""
+}
+
+func (x *SqlTable) GetTimestamps() *SqlTimestamps {
+	if x != nil {
+		return x.Timestamps
+	}
+	return nil
+}
+
 type SqlType struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          SqlFiledType           `protobuf:"varint,1,opt,name=type,proto3,enum=sql.SqlFiledType" json:"type,omitempty"`
@@ -272,7 +399,7 @@ type SqlType struct {
 
 func (x *SqlType) Reset() {
 	*x = SqlType{}
-	mi := &file_pgx_proto_msgTypes[1]
+	mi := &file_pgx_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -284,7 +411,7 @@ func (x *SqlType) String() string {
 func (*SqlType) ProtoMessage() {}
 
 func (x *SqlType) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[1]
+	mi := &file_pgx_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,7 +424,7 @@ func (x *SqlType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlType.ProtoReflect.Descriptor instead.
 func (*SqlType) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{1}
+	return file_pgx_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *SqlType) GetType() SqlFiledType {
@@ -340,7 +467,7 @@ type SqlConstraint struct {
 
 func (x *SqlConstraint) Reset() {
 	*x = SqlConstraint{}
-	mi := &file_pgx_proto_msgTypes[2]
+	mi := &file_pgx_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -352,7 +479,7 @@ func (x *SqlConstraint) String() string {
 func (*SqlConstraint) ProtoMessage() {}
 
 func (x *SqlConstraint) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[2]
+	mi := &file_pgx_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -365,7 +492,7 @@ func (x *SqlConstraint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlConstraint.ProtoReflect.Descriptor instead.
 func (*SqlConstraint) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{2}
+	return file_pgx_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SqlConstraint) GetUnique() bool {
@@ -409,7 +536,7 @@ type SqlVirtualField struct {
 
 func (x *SqlVirtualField) Reset() {
 	*x = SqlVirtualField{}
-	mi := &file_pgx_proto_msgTypes[3]
+	mi := &file_pgx_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -421,7 +548,7 @@ func (x *SqlVirtualField) String() string {
 func (*SqlVirtualField) ProtoMessage() {}
 
 func (x *SqlVirtualField) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[3]
+	mi := &file_pgx_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -434,7 +561,7 @@ func (x *SqlVirtualField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlVirtualField.ProtoReflect.Descriptor instead.
 func (*SqlVirtualField) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{3}
+	return file_pgx_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SqlVirtualField) GetSqlName() string {
@@ -486,7 +613,7 @@ type SqlField struct {
 
 func (x *SqlField) Reset() {
 	*x = SqlField{}
-	mi := &file_pgx_proto_msgTypes[4]
+	mi := &file_pgx_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -498,7 +625,7 @@ func (x *SqlField) String() string {
 func (*SqlField) ProtoMessage() {}
 
 func (x *SqlField) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[4]
+	mi := &file_pgx_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +638,7 @@ func (x *SqlField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlField.ProtoReflect.Descriptor instead.
 func (*SqlField) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{4}
+	return file_pgx_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SqlField) GetSkip() bool {
@@ -562,7 +689,7 @@ type SqlRelation struct {
 
 func (x *SqlRelation) Reset() {
 	*x = SqlRelation{}
-	mi := &file_pgx_proto_msgTypes[5]
+	mi := &file_pgx_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -574,7 +701,7 @@ func (x *SqlRelation) String() string {
 func (*SqlRelation) ProtoMessage() {}
 
 func (x *SqlRelation) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[5]
+	mi := &file_pgx_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -587,7 +714,7 @@ func (x *SqlRelation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlRelation.ProtoReflect.Descriptor instead.
 func (*SqlRelation) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{5}
+	return file_pgx_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SqlRelation) GetRelation() isSqlRelation_Relation {
@@ -643,7 +770,7 @@ type CasterFn struct {
 
 func (x *CasterFn) Reset() {
 	*x = CasterFn{}
-	mi := &file_pgx_proto_msgTypes[6]
+	mi := &file_pgx_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -655,7 +782,7 @@ func (x *CasterFn) String() string {
 func (*CasterFn) ProtoMessage() {}
 
 func (x *CasterFn) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[6]
+	mi := &file_pgx_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -668,7 +795,7 @@ func (x *CasterFn) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CasterFn.ProtoReflect.Descriptor instead.
 func (*CasterFn) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{6}
+	return file_pgx_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CasterFn) GetName() string {
@@ -716,7 +843,7 @@ type ParsedField struct {
 
 func (x *ParsedField) Reset() {
 	*x = ParsedField{}
-	mi := &file_pgx_proto_msgTypes[7]
+	mi := &file_pgx_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -728,7 +855,7 @@ func (x *ParsedField) String() string {
 func (*ParsedField) ProtoMessage() {}
 
 func (x *ParsedField) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[7]
+	mi := &file_pgx_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -741,7 +868,7 @@ func (x *ParsedField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ParsedField.ProtoReflect.Descriptor instead.
 func (*ParsedField) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{7}
+	return file_pgx_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ParsedField) GetTypeInfo() *ParsedField_TypeInfo {
@@ -819,7 +946,7 @@ type SqlRelation_OneToMany struct {
 
 func (x *SqlRelation_OneToMany) Reset() {
 	*x = SqlRelation_OneToMany{}
-	mi := &file_pgx_proto_msgTypes[8]
+	mi := &file_pgx_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -831,7 +958,7 @@ func (x *SqlRelation_OneToMany) String() string {
 func (*SqlRelation_OneToMany) ProtoMessage() {}
 
 func (x *SqlRelation_OneToMany) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[8]
+	mi := &file_pgx_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -844,7 +971,7 @@ func (x *SqlRelation_OneToMany) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlRelation_OneToMany.ProtoReflect.Descriptor instead.
 func (*SqlRelation_OneToMany) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{5, 0}
+	return file_pgx_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *SqlRelation_OneToMany) GetRefName() string {
@@ -888,7 +1015,7 @@ type SqlRelation_ManyToMany struct {
 
 func (x *SqlRelation_ManyToMany) Reset() {
 	*x = SqlRelation_ManyToMany{}
-	mi := &file_pgx_proto_msgTypes[9]
+	mi := &file_pgx_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -900,7 +1027,7 @@ func (x *SqlRelation_ManyToMany) String() string {
 func (*SqlRelation_ManyToMany) ProtoMessage() {}
 
 func (x *SqlRelation_ManyToMany) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[9]
+	mi := &file_pgx_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -913,7 +1040,7 @@ func (x *SqlRelation_ManyToMany) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SqlRelation_ManyToMany.ProtoReflect.Descriptor instead.
 func (*SqlRelation_ManyToMany) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{5, 1}
+	return file_pgx_proto_rawDescGZIP(), []int{7, 1}
 }
 
 func (x *SqlRelation_ManyToMany) GetTable() *SqlTable {
@@ -968,7 +1095,7 @@ type ParsedField_TypeInfo struct {
 
 func (x *ParsedField_TypeInfo) Reset() {
 	*x = ParsedField_TypeInfo{}
-	mi := &file_pgx_proto_msgTypes[10]
+	mi := &file_pgx_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -980,7 +1107,7 @@ func (x *ParsedField_TypeInfo) String() string {
 func (*ParsedField_TypeInfo) ProtoMessage() {}
 
 func (x *ParsedField_TypeInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_pgx_proto_msgTypes[10]
+	mi := &file_pgx_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -993,7 +1120,7 @@ func (x *ParsedField_TypeInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ParsedField_TypeInfo.ProtoReflect.Descriptor instead.
 func (*ParsedField_TypeInfo) Descriptor() ([]byte, []int) {
-	return file_pgx_proto_rawDescGZIP(), []int{7, 0}
+	return file_pgx_proto_rawDescGZIP(), []int{9, 0}
 }
 
 func (x *ParsedField_TypeInfo) GetSqlType() *SqlType {
@@ -1118,13 +1245,26 @@ var File_pgx_proto protoreflect.FileDescriptor
 
 const file_pgx_proto_rawDesc = "" +
 	"\n" +
-	"\tpgx.proto\x12\x03sql\x1a google/protobuf/descriptor.proto\"\xb8\x01\n" +
+	"\tpgx.proto\x12\x03sql\x1a google/protobuf/descriptor.proto\"'\n" +
+	"\rSqlSoftDelete\x12\x16\n" +
+	"\x06column\x18\x01 \x01(\tR\x06column\"M\n" +
+	"\rSqlTimestamps\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x01 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x02 \x01(\tR\tupdatedAt\"\xc8\x02\n" +
 	"\bSqlTable\x12\x1a\n" +
 	"\bgenerate\x18\x01 \x01(\bR\bgenerate\x12\"\n" +
 	"\n" +
 	"table_name\x18\x02 \x01(\tH\x00R\ttableName\x88\x01\x01\x12;\n" +
 	"\x0evirtual_fields\x18\x04 \x03(\v2\x14.sql.SqlVirtualFieldR\rvirtualFields\x12 \n" +
-	"\vconstraints\x18\x05 \x03(\tR\vconstraintsB\r\n" +
+	"\vconstraints\x18\x05 \x03(\tR\vconstraints\x123\n" +
+	"\vsoft_delete\x18\x06 \x01(\v2\x12.sql.SqlSoftDeleteR\n" +
+	"softDelete\x12%\n" +
+	"\x0eversion_column\x18\a \x01(\tR\rversionColumn\x122\n" +
+	"\n" +
+	"timestamps\x18\b \x01(\v2\x12.sql.SqlTimestampsR\n" +
+	"timestampsB\r\n" +
 	"\v_table_name\"\x97\x01\n" +
 	"\aSqlType\x12%\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x11.sql.SqlFiledTypeR\x04type\x12\x17\n" +
@@ -1265,53 +1405,57 @@ func file_pgx_proto_rawDescGZIP() []byte {
 }
 
 var file_pgx_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_pgx_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_pgx_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_pgx_proto_goTypes = []any{
 	(SqlFiledType)(0),                   // 0: sql.SqlFiledType
 	(ParsedField_ProtoKind)(0),          // 1: sql.ParsedField.ProtoKind
-	(*SqlTable)(nil),                    // 2: sql.SqlTable
-	(*SqlType)(nil),                     // 3: sql.SqlType
-	(*SqlConstraint)(nil),               // 4: sql.SqlConstraint
-	(*SqlVirtualField)(nil),             // 5: sql.SqlVirtualField
-	(*SqlField)(nil),                    // 6: sql.SqlField
-	(*SqlRelation)(nil),                 // 7: sql.SqlRelation
-	(*CasterFn)(nil),                    // 8: sql.CasterFn
-	(*ParsedField)(nil),                 // 9: sql.ParsedField
-	(*SqlRelation_OneToMany)(nil),       // 10: sql.SqlRelation.OneToMany
-	(*SqlRelation_ManyToMany)(nil),      // 11: sql.SqlRelation.ManyToMany
-	(*ParsedField_TypeInfo)(nil),        // 12: sql.ParsedField.TypeInfo
-	(*descriptorpb.FileOptions)(nil),    // 13: google.protobuf.FileOptions
-	(*descriptorpb.MessageOptions)(nil), // 14: google.protobuf.MessageOptions
-	(*descriptorpb.FieldOptions)(nil),   // 15: google.protobuf.FieldOptions
+	(*SqlSoftDelete)(nil),               // 2: sql.SqlSoftDelete
+	(*SqlTimestamps)(nil),               // 3: sql.SqlTimestamps
+	(*SqlTable)(nil),                    // 4: sql.SqlTable
+	(*SqlType)(nil),                     // 5: sql.SqlType
+	(*SqlConstraint)(nil),               // 6: sql.SqlConstraint
+	(*SqlVirtualField)(nil),             // 7: sql.SqlVirtualField
+	(*SqlField)(nil),                    // 8: sql.SqlField
+	(*SqlRelation)(nil),                 // 9: sql.SqlRelation
+	(*CasterFn)(nil),                    // 10: sql.CasterFn
+	(*ParsedField)(nil),                 // 11: sql.ParsedField
+	(*SqlRelation_OneToMany)(nil),       // 12: sql.SqlRelation.OneToMany
+	(*SqlRelation_ManyToMany)(nil),      // 13: sql.SqlRelation.ManyToMany
+	(*ParsedField_TypeInfo)(nil),        // 14: sql.ParsedField.TypeInfo
+	(*descriptorpb.FileOptions)(nil),    // 15: google.protobuf.FileOptions
+	(*descriptorpb.MessageOptions)(nil), // 16: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 17: google.protobuf.FieldOptions
 }
 var file_pgx_proto_depIdxs = []int32{
-	5,  // 0: sql.SqlTable.virtual_fields:type_name -> sql.SqlVirtualField
-	0,  // 1: sql.SqlType.type:type_name -> sql.SqlFiledType
-	3,  // 2: sql.SqlVirtualField.sql_type:type_name -> sql.SqlType
-	4,  // 3: sql.SqlVirtualField.constraints:type_name -> sql.SqlConstraint
-	3,  // 4: sql.SqlField.sql_type:type_name -> sql.SqlType
-	4,  // 5: sql.SqlField.constraints:type_name -> sql.SqlConstraint
-	10, // 6: sql.SqlRelation.one_to_many:type_name -> sql.SqlRelation.OneToMany
-	11, // 7: sql.SqlRelation.many_to_many:type_name -> sql.SqlRelation.ManyToMany
-	12, // 8: sql.ParsedField.type_info:type_name -> sql.ParsedField.TypeInfo
-	4,  // 9: sql.ParsedField.constraint:type_name -> sql.SqlConstraint
-	2,  // 10: sql.SqlRelation.ManyToMany.table:type_name -> sql.SqlTable
-	3,  // 11: sql.ParsedField.TypeInfo.sql_type:type_name -> sql.SqlType
-	8,  // 12: sql.ParsedField.TypeInfo.up_caster_fn:type_name -> sql.CasterFn
-	8,  // 13: sql.ParsedField.TypeInfo.down_caster_fn:type_name -> sql.CasterFn
-	1,  // 14: sql.ParsedField.TypeInfo.proto_kind:type_name -> sql.ParsedField.ProtoKind
-	13, // 15: sql.additional_code:extendee -> google.protobuf.FileOptions
-	14, // 16: sql.sql_table:extendee -> google.protobuf.MessageOptions
-	15, // 17: sql.sql_field:extendee -> google.protobuf.FieldOptions
-	15, // 18: sql.sql_relation:extendee -> google.protobuf.FieldOptions
-	2,  // 19: sql.sql_table:type_name -> sql.SqlTable
-	6,  // 20: sql.sql_field:type_name -> sql.SqlField
-	7,  // 21: sql.sql_relation:type_name -> sql.SqlRelation
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	19, // [19:22] is the sub-list for extension type_name
-	15, // [15:19] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	7,  // 0: sql.SqlTable.virtual_fields:type_name -> sql.SqlVirtualField
+	2,  // 1: sql.SqlTable.soft_delete:type_name -> sql.SqlSoftDelete
+	3,  // 2: sql.SqlTable.timestamps:type_name -> sql.SqlTimestamps
+	0,  // 3: sql.SqlType.type:type_name -> sql.SqlFiledType
+	5,  // 4: sql.SqlVirtualField.sql_type:type_name -> sql.SqlType
+	6,  // 5: sql.SqlVirtualField.constraints:type_name -> sql.SqlConstraint
+	5,  // 6: sql.SqlField.sql_type:type_name -> sql.SqlType
+	6,  // 7: sql.SqlField.constraints:type_name -> sql.SqlConstraint
+	12, // 8: sql.SqlRelation.one_to_many:type_name -> sql.SqlRelation.OneToMany
+	13, // 9: sql.SqlRelation.many_to_many:type_name -> sql.SqlRelation.ManyToMany
+	14, // 10: sql.ParsedField.type_info:type_name -> sql.ParsedField.TypeInfo
+	6,  // 11: sql.ParsedField.constraint:type_name -> sql.SqlConstraint
+	4,  // 12: sql.SqlRelation.ManyToMany.table:type_name -> sql.SqlTable
+	5,  // 13: sql.ParsedField.TypeInfo.sql_type:type_name -> sql.SqlType
+	10, // 14: sql.ParsedField.TypeInfo.up_caster_fn:type_name -> sql.CasterFn
+	10, // 15: sql.ParsedField.TypeInfo.down_caster_fn:type_name -> sql.CasterFn
+	1,  // 16: sql.ParsedField.TypeInfo.proto_kind:type_name -> sql.ParsedField.ProtoKind
+	15, // 17: sql.additional_code:extendee -> google.protobuf.FileOptions
+	16, // 18: sql.sql_table:extendee -> google.protobuf.MessageOptions
+	17, // 19: sql.sql_field:extendee -> google.protobuf.FieldOptions
+	17, // 20: sql.sql_relation:extendee -> google.protobuf.FieldOptions
+	4,  // 21: sql.sql_table:type_name -> sql.SqlTable
+	8,  // 22: sql.sql_field:type_name -> sql.SqlField
+	9,  // 23: sql.sql_relation:type_name -> sql.SqlRelation
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	21, // [21:24] is the sub-list for extension type_name
+	17, // [17:21] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_pgx_proto_init() }
@@ -1319,21 +1463,21 @@ func file_pgx_proto_init() {
 	if File_pgx_proto != nil {
 		return
 	}
-	file_pgx_proto_msgTypes[0].OneofWrappers = []any{}
-	file_pgx_proto_msgTypes[1].OneofWrappers = []any{}
-	file_pgx_proto_msgTypes[5].OneofWrappers = []any{
+	file_pgx_proto_msgTypes[2].OneofWrappers = []any{}
+	file_pgx_proto_msgTypes[3].OneofWrappers = []any{}
+	file_pgx_proto_msgTypes[7].OneofWrappers = []any{
 		(*SqlRelation_OneToMany_)(nil),
 		(*SqlRelation_ManyToMany_)(nil),
 	}
-	file_pgx_proto_msgTypes[9].OneofWrappers = []any{}
-	file_pgx_proto_msgTypes[10].OneofWrappers = []any{}
+	file_pgx_proto_msgTypes[11].OneofWrappers = []any{}
+	file_pgx_proto_msgTypes[12].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pgx_proto_rawDesc), len(file_pgx_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   11,
+			NumMessages:   13,
 			NumExtensions: 4,
 			NumServices:   0,
 		},
@@ -201,6 +201,17 @@ func (t *Field) GoName() string {
 	return strcase.ToCamel(string(protoreflect.FullName(t.ProtoName).Name()))
 }
 
+// ProtoFieldName — name of the field in its message
+func (t *Field) ProtoFieldName() string {
+	return string(protoreflect.FullName(t.ProtoName).Name())
+}
+
+// VarName — lowerCamel identifier of the column for generated locals and
+// parameters, see goVarName
+func (t *Field) VarName() string {
+	return goVarName(t.GoName())
+}
+
 func CollectFieldsFromMessage(message *protogen.Message) []*Field {
 	messageOpts := message.Desc.Options().(*descriptorpb.MessageOptions)
 	msgOpts, _ := proto.GetExtension(messageOpts, protopgx.E_SqlTable).(*protopgx.SqlTable)
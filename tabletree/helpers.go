@@ -7,6 +7,7 @@ import (
 	"github.com/yaroher/protoc-gen-pgx-orm/protopgx"
 	"go.uber.org/zap"
 	"go/token"
+	"go/types"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -209,3 +210,22 @@ func fieldGoTypeClear(field *protogen.Field) string {
 func isFieldOneOf(field *protogen.Field) bool {
 	return field.Oneof != nil && strings.Index(field.GoName, "_") != 0
 }
+
+// generatedNames are identifiers used next to column variables in generated
+// table builders and repository helpers
+var generatedNames = map[string]bool{
+	"ctx": true, "opts": true, "r": true, "entity": true,
+	"newColumn": true, "newTable": true, "fieldAliasImpl": true, "pathField": true,
+}
+
+// goVarName converts name to lowerCamel and suffixes it with "_" when it is a
+// keyword, a predeclared identifier or a name used by generated code, filter*
+// are orm filter flags of path fields
+func goVarName(name string) string {
+	ret := strcase.ToLowerCamel(name)
+	if token.IsKeyword(ret) || types.Universe.Lookup(ret) != nil || generatedNames[ret] ||
+		strings.HasPrefix(ret, "filter") {
+		return ret + "_"
+	}
+	return ret
+}
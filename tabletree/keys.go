@@ -0,0 +1,254 @@
+package tabletree
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	tableKeyRe        = regexp.MustCompile(`(?i)^\s*(?:CONSTRAINT\s+\S+\s+)?(PRIMARY\s+KEY|UNIQUE)\s*\(([^)]*)\)`)
+	tableConstraintRe = regexp.MustCompile(`(?i)^\s*(?:CONSTRAINT\s+("[^"]+"|\S+)\s+)?(PRIMARY\s+KEY|UNIQUE|FOREIGN\s+KEY|CHECK)\s*\(([^)]*)\)`)
+	foreignKeyRe      = regexp.MustCompile(`(?i)FOREIGN\s+KEY\s*\(([^)]*)\)`)
+	// identifierRe matches string literals too, so their words are skipped
+	identifierRe = regexp.MustCompile(`'(?:[^']|'')*'|"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// PrimaryKeyFields returns primary key columns declared on the field or in the
+// table constraints, several declared keys fail generation as PostgreSQL
+// allows one primary key per table
+func (t *TableNode) PrimaryKeyFields() []*Field {
+	keys := t.keys(true)
+	if len(keys) > 1 {
+		declared := make([]string, 0, len(keys))
+		for _, key := range keys {
+			columns := make([]string, 0, len(key))
+			for _, field := range key {
+				columns = append(columns, field.SqlFieldName())
+			}
+			declared = append(declared, "("+strings.Join(columns, ", ")+")")
+		}
+		panic(fmt.Sprintf("table %s declares %d primary keys %s, declare a composite key "+
+			"with a PRIMARY KEY (...) table constraint instead", t.Name, len(keys), strings.Join(declared, ", ")))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys[0]
+}
+
+// UniqueFields returns unique column sets declared on fields or in the table
+// constraints, in declaration order
+func (t *TableNode) UniqueFields() [][]*Field {
+	return t.keys(false)
+}
+
+func (t *TableNode) keys(primary bool) [][]*Field {
+	ret := make([][]*Field, 0)
+	for _, field := range t.Fields {
+		constraint := field.GetConstraint()
+		declared := strings.ToUpper(constraint.GetConstraint())
+		if primary && (constraint.GetPrimaryKey() || strings.Contains(declared, "PRIMARY KEY")) ||
+			!primary && (constraint.GetUnique() || strings.Contains(declared, "UNIQUE")) {
+			ret = append(ret, []*Field{field})
+		}
+	}
+	for _, constraint := range t.Constraints {
+		match := tableKeyRe.FindStringSubmatch(constraint)
+		if match == nil || strings.HasPrefix(strings.ToUpper(match[1]), "PRIMARY") != primary {
+			continue
+		}
+		fields := make([]*Field, 0)
+		for _, name := range splitColumns(match[2]) {
+			field, ok := t.findSqlField(name)
+			if !ok {
+				fields = nil
+				break
+			}
+			fields = append(fields, field)
+		}
+		if len(fields) > 0 {
+			ret = append(ret, fields)
+		}
+	}
+	return ret
+}
+
+func (t *TableNode) findSqlField(name string) (*Field, bool) {
+	for _, field := range t.Fields {
+		if field.SqlFieldName() == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// KeySet is a primary or unique key used for generated repository lookups,
+// e.g. GetByPK or GetByEmail
+type KeySet struct {
+	Name   string
+	Fields []*Field
+}
+
+// Plural names the batch lookup, e.g. GetByPKs or GetByEmails
+func (k *KeySet) Plural() string {
+	for _, suffix := range []string{"s", "x", "sh", "ch"} {
+		if strings.HasSuffix(k.Name, suffix) {
+			return k.Name + "es"
+		}
+	}
+	return k.Name + "s"
+}
+
+// PluralVarName names the parameter of the batch lookup
+func (k *KeySet) PluralVarName() string {
+	return goVarName(k.Plural())
+}
+
+// Single reports whether the key has one column, batch lookups are generated
+// only for such keys
+func (k *KeySet) Single() bool {
+	return len(k.Fields) == 1
+}
+
+// KeySets returns the primary key named "PK" followed by unique keys named
+// after their columns
+func (t *TableNode) KeySets() []*KeySet {
+	ret := make([]*KeySet, 0)
+	pk := t.PrimaryKeyFields()
+	if len(pk) > 0 {
+		ret = append(ret, &KeySet{Name: "PK", Fields: pk})
+	}
+	for _, unique := range t.UniqueFields() {
+		if sameFields(unique, pk) {
+			continue
+		}
+		names := make([]string, 0, len(unique))
+		for _, field := range unique {
+			names = append(names, field.GoName())
+		}
+		ret = append(ret, &KeySet{Name: strings.Join(names, "And"), Fields: unique})
+	}
+	return ret
+}
+
+func sameFields(a, b []*Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NamedConstraint maps a constraint name to its columns so database errors
+// can be reported per column
+type NamedConstraint struct {
+	Name    string
+	Columns []string
+}
+
+// NamedConstraints returns constraints of the table with names PostgreSQL
+// gives them by default (table_pkey, table_cols_key, table_cols_fkey,
+// table_col_check) or with the explicit CONSTRAINT name. Unnamed table level
+// CHECK constraints are skipped since their default name depends on the
+// expression.
+func (t *TableNode) NamedConstraints() []*NamedConstraint {
+	table := t.SqlTableName()
+	ret := make([]*NamedConstraint, 0)
+	add := func(name string, columns ...string) {
+		for _, c := range ret {
+			if c.Name == name {
+				return
+			}
+		}
+		ret = append(ret, &NamedConstraint{Name: name, Columns: columns})
+	}
+	if pk := t.PrimaryKeyFields(); len(pk) > 0 {
+		columns := make([]string, 0, len(pk))
+		for _, field := range pk {
+			columns = append(columns, field.SqlFieldName())
+		}
+		add(table+"_pkey", columns...)
+	}
+	for _, field := range t.Fields {
+		column := field.SqlFieldName()
+		constraint := field.GetConstraint()
+		declared := strings.ToUpper(constraint.GetConstraint())
+		if constraint.GetUnique() || strings.Contains(declared, "UNIQUE") {
+			add(table+"_"+column+"_key", column)
+		}
+		if match := foreignKeyRe.FindStringSubmatch(constraint.GetConstraint()); match != nil {
+			columns := splitColumns(match[1])
+			add(table+"_"+strings.Join(columns, "_")+"_fkey", columns...)
+		} else if strings.Contains(declared, "REFERENCES") {
+			add(table+"_"+column+"_fkey", column)
+		}
+		if strings.Contains(declared, "CHECK") {
+			add(table+"_"+column+"_check", column)
+		}
+	}
+	for _, constraint := range t.Constraints {
+		match := tableConstraintRe.FindStringSubmatch(constraint)
+		if match == nil {
+			continue
+		}
+		name := strings.Trim(match[1], `"`)
+		kind := strings.Join(strings.Fields(strings.ToUpper(match[2])), " ")
+		if kind == "CHECK" {
+			if name != "" {
+				add(name, t.referencedColumns(constraint)...)
+			}
+			continue
+		}
+		columns := splitColumns(match[3])
+		if name == "" {
+			switch kind {
+			case "PRIMARY KEY":
+				name = table + "_pkey"
+			case "UNIQUE":
+				name = table + "_" + strings.Join(columns, "_") + "_key"
+			case "FOREIGN KEY":
+				name = table + "_" + strings.Join(columns, "_") + "_fkey"
+			}
+		}
+		add(name, columns...)
+	}
+	return ret
+}
+
+func splitColumns(list string) []string {
+	ret := make([]string, 0)
+	for _, name := range strings.Split(list, ",") {
+		ret = append(ret, strings.Trim(strings.TrimSpace(name), `"`))
+	}
+	return ret
+}
+
+// referencedColumns returns table columns mentioned in the expression, words
+// of string literals are not columns and quoted identifiers match exactly
+func (t *TableNode) referencedColumns(expr string) []string {
+	words := identifierRe.FindAllString(expr, -1)
+	ret := make([]string, 0)
+	for _, field := range t.Fields {
+		column := field.SqlFieldName()
+		for _, word := range words {
+			var match bool
+			switch word[0] {
+			case '\'':
+			case '"':
+				match = strings.ReplaceAll(word[1:len(word)-1], `""`, `"`) == column
+			default:
+				match = strings.EqualFold(word, column)
+			}
+			if match {
+				ret = append(ret, column)
+				break
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,122 @@
+package tabletree
+
+import (
+	"fmt"
+	"github.com/yaroher/protoc-gen-pgx-orm/protopgx"
+	"strings"
+	"testing"
+)
+
+func newKeysTestField(name string, constraint *protopgx.SqlConstraint) *Field {
+	return &Field{ParsedField: &protopgx.ParsedField{ProtoName: "test.Member." + name, Constraint: constraint}}
+}
+
+func TestNamedConstraints(t *testing.T) {
+	node := &TableNode{
+		Name: "test.Member",
+		Fields: []*Field{
+			newKeysTestField("id", &protopgx.SqlConstraint{PrimaryKey: true}),
+			newKeysTestField("email", &protopgx.SqlConstraint{Unique: true}),
+			newKeysTestField("team_id", &protopgx.SqlConstraint{Constraint: "BIGINT REFERENCES teams(id)"}),
+			newKeysTestField("slug", nil),
+			newKeysTestField("age", nil),
+		},
+		Constraints: []string{
+			"UNIQUE (team_id, slug)",
+			"CONSTRAINT check_age CHECK (age >= 0)",
+			"CHECK (slug <> '')",
+		},
+	}
+	got := make([]string, 0)
+	for _, c := range node.NamedConstraints() {
+		got = append(got, fmt.Sprint(c.Name, c.Columns))
+	}
+	want := "[member_pkey[id] member_email_key[email] member_team_id_fkey[team_id] " +
+		"member_team_id_slug_key[team_id slug] check_age[age]]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("constraints mismatch:\nwant: %s\ngot : %s", want, fmt.Sprint(got))
+	}
+
+	keys := make([]string, 0)
+	for _, k := range node.KeySets() {
+		keys = append(keys, k.Name, k.Plural())
+	}
+	if fmt.Sprint(keys) != "[PK PKs Email Emails TeamIdAndSlug TeamIdAndSlugs]" {
+		t.Fatalf("key sets mismatch: %v", keys)
+	}
+}
+
+func TestKeyVarNames(t *testing.T) {
+	node := &TableNode{
+		Name: "test.Member",
+		Fields: []*Field{
+			newKeysTestField("type", &protopgx.SqlConstraint{PrimaryKey: true}),
+			newKeysTestField("ctx", &protopgx.SqlConstraint{Unique: true}),
+			newKeysTestField("team_id", &protopgx.SqlConstraint{Unique: true}),
+		},
+	}
+	names := make([]string, 0)
+	for _, k := range node.KeySets() {
+		for _, field := range k.Fields {
+			names = append(names, field.VarName())
+		}
+		names = append(names, k.PluralVarName())
+	}
+	if fmt.Sprint(names) != "[type_ pks ctx_ ctxes teamId teamIds]" {
+		t.Fatalf("var names mismatch: %v", names)
+	}
+}
+
+func TestVersionColumn(t *testing.T) {
+	version := newKeysTestField("version", nil)
+	version.TypeInfo = &protopgx.ParsedField_TypeInfo{SqlType: &protopgx.SqlType{Type: protopgx.SqlFiledType_BIGINT}}
+	node := &TableNode{Name: "test.Member", Fields: []*Field{version, newKeysTestField("slug", nil)}}
+	if got := versionColumn(node, "version"); got != version {
+		t.Fatalf("version field mismatch: %v", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("version column which is not an integer field must be rejected")
+		}
+	}()
+	versionColumn(node, "slug")
+}
+
+func TestCheckConstraintColumns(t *testing.T) {
+	node := &TableNode{
+		Name: "test.Payment",
+		Fields: []*Field{
+			newKeysTestField("payment_method_type", nil),
+			newKeysTestField("card", nil),
+			newKeysTestField("cash", nil),
+			newKeysTestField("amount", nil),
+		},
+		Constraints: []string{
+			"CONSTRAINT check_payment_method CHECK (payment_method_type IN ('card', 'bank_transfer', 'crypto', 'cash'))",
+			`CONSTRAINT check_amount CHECK ("amount" > 0 AND "Card" IS NULL)`,
+		},
+	}
+	got := make([]string, 0)
+	for _, c := range node.NamedConstraints() {
+		got = append(got, fmt.Sprint(c.Name, c.Columns))
+	}
+	if fmt.Sprint(got) != "[check_payment_method[payment_method_type] check_amount[amount]]" {
+		t.Fatalf("check columns mismatch: %v", got)
+	}
+}
+
+func TestSeveralPrimaryKeys(t *testing.T) {
+	node := &TableNode{
+		Name: "test.Member",
+		Fields: []*Field{
+			newKeysTestField("id", &protopgx.SqlConstraint{PrimaryKey: true}),
+			newKeysTestField("ulid", &protopgx.SqlConstraint{PrimaryKey: true}),
+		},
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "2 primary keys (id), (ulid)") {
+			t.Fatalf("expected several primary keys panic, got %v", r)
+		}
+	}()
+	node.PrimaryKeyFields()
+}
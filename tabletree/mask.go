@@ -0,0 +1,45 @@
+package tabletree
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MaskPath maps a google.protobuf.FieldMask path to the columns it covers
+type MaskPath struct {
+	Path   string
+	Fields []*Field
+}
+
+// MaskPaths returns mask paths of the proto message: plain and oneof member
+// fields, "embed.field" for flattened embedded messages, and the embedded
+// message or oneof name itself covering all of its columns. Virtual fields
+// have no proto path.
+func (t *TableNode) MaskPaths() []*MaskPath {
+	ret := make([]*MaskPath, 0, len(t.Fields))
+	groups := make(map[string]*MaskPath)
+	addToGroup := func(name string, field *Field) {
+		group, ok := groups[name]
+		if !ok {
+			group = &MaskPath{Path: name}
+			groups[name] = group
+			ret = append(ret, group)
+		}
+		group.Fields = append(group.Fields, field)
+	}
+	for _, field := range t.Fields {
+		if field.Virtual {
+			continue
+		}
+		name := string(protoreflect.FullName(field.ProtoName).Name())
+		if field.Embedded {
+			embed := string(protoreflect.FullName(field.GetFromEmbeddedMessageField()).Name())
+			addToGroup(embed, field)
+			name = embed + "." + name
+		}
+		if field.GetFromOneOfField() != "" {
+			addToGroup(string(protoreflect.FullName(field.GetFromOneOfField()).Name()), field)
+		}
+		ret = append(ret, &MaskPath{Path: name, Fields: []*Field{field}})
+	}
+	return ret
+}
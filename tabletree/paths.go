@@ -0,0 +1,73 @@
+package tabletree
+
+import (
+	"github.com/yaroher/protoc-gen-pgx-orm/protopgx"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"strings"
+)
+
+// PathField maps an API field path used in filter and order_by strings to
+// its column
+type PathField struct {
+	Path  string
+	Field *Field
+}
+
+// PathFields returns paths of every column: proto field names, "embed.field"
+// for flattened embedded messages and sql names of virtual fields
+func (t *TableNode) PathFields() []*PathField {
+	ret := make([]*PathField, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		if field.Virtual {
+			ret = append(ret, &PathField{Path: field.SqlFieldName(), Field: field})
+			continue
+		}
+		name := string(protoreflect.FullName(field.ProtoName).Name())
+		if field.Embedded {
+			name = string(protoreflect.FullName(field.GetFromEmbeddedMessageField()).Name()) + "." + name
+		}
+		ret = append(ret, &PathField{Path: name, Field: field})
+	}
+	return ret
+}
+
+// Kind — orm filter value kind of the column
+func (p *PathField) Kind() string {
+	switch p.Field.TypeInfo.SqlType.GetType() {
+	case protopgx.SqlFiledType_TEXT, protopgx.SqlFiledType_CHAR:
+		return "filterString"
+	case protopgx.SqlFiledType_INTEGER, protopgx.SqlFiledType_BIGINT, protopgx.SqlFiledType_SMALLINT:
+		return "filterInt"
+	case protopgx.SqlFiledType_DOUBLE_PRECISION, protopgx.SqlFiledType_REAL:
+		return "filterFloat"
+	case protopgx.SqlFiledType_BOOLEAN:
+		return "filterBool"
+	case protopgx.SqlFiledType_TIMESTAMPTZ:
+		return "filterTime"
+	default:
+		return "filterUnsupported"
+	}
+}
+
+var operandFilterFlags = map[string]string{
+	"CommonOperator": "filterEq",
+	"ScalarOperator": "filterCompare",
+	"LikeOperator":   "filterLike",
+	"IsNullOperator": "filterNull",
+}
+
+// Operands — orm filter operand flags built from Field.AvailableOperands.
+// Array columns are only matched by element with ":".
+func (p *PathField) Operands() string {
+	flags := make([]string, 0, 4)
+	if p.Field.TypeInfo.IsArray {
+		flags = append(flags, "filterHas")
+	}
+	for _, operand := range p.Field.AvailableOperands() {
+		if p.Field.TypeInfo.IsArray && operand != "IsNullOperator" {
+			continue
+		}
+		flags = append(flags, operandFilterFlags[operand])
+	}
+	return strings.Join(flags, " | ")
+}
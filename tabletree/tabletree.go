@@ -22,7 +22,32 @@ type BackwardRelation struct {
 	From      *TableNode
 	FromField *Field
 	ToField   *Field
+	// Name of the repeated field holding related messages, one-to-many only
+	Name protoreflect.Name
 }
+
+func (b *BackwardRelation) GoName() string {
+	return strcase.ToCamel(string(b.Name))
+}
+
+// ManyToMany — relation of the table to Target through the Join table which
+// rows reference both of them
+type ManyToMany struct {
+	Name protoreflect.Name
+	// OwnerKey — key column of the table referenced by Join
+	OwnerKey *Field
+	Join     *TableNode
+	// OwnerField and TargetField — columns of Join referencing the table and Target
+	OwnerField  *Field
+	TargetField *Field
+	Target      *TableNode
+	TargetKey   *Field
+}
+
+func (m *ManyToMany) GoName() string {
+	return strcase.ToCamel(string(m.Name))
+}
+
 type Relation struct {
 	To        *TableNode
 	ToField   *Field
@@ -47,6 +72,16 @@ type TableNode struct {
 	Embeds    map[protoreflect.FullName]*Encapsulation
 	Relations []*Relation
 	Backwards []*BackwardRelation
+	// Associations — many-to-many relations owned by the table
+	Associations []*ManyToMany
+	// SoftDelete — deletion time column, nil when rows are deleted for real
+	SoftDelete *Field
+	// Version — optimistic locking column, nil when updates are not checked
+	Version *Field
+	// CreatedAt, UpdatedAt — timestamp columns maintained by repositories, nil
+	// without SqlTable.timestamps
+	CreatedAt *Field
+	UpdatedAt *Field
 }
 
 func (t *TableNode) ProtoName() string {
@@ -120,6 +155,18 @@ func (t *TableNode) ToEmbeddedFieldsCast() string {
 	return buff.String()
 }
 
+// OneToMany returns relations which children are preloaded into repeated
+// fields of the message
+func (t *TableNode) OneToMany() []*BackwardRelation {
+	ret := make([]*BackwardRelation, 0, len(t.Backwards))
+	for _, b := range t.Backwards {
+		if !t.Virtual && !b.From.Virtual && b.Name != "" {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
 func (t *TableNode) HasVirtualFields() bool {
 	for _, field := range t.Fields {
 		if field.Virtual {
@@ -152,6 +199,53 @@ func (t *TableNode) AllUserCasters() []*protopgx.CasterFn {
 	return ret
 }
 
+// tableColumn finds the column by sql name or adds the virtual field, the
+// found column must have the sql type of the virtual field
+func tableColumn(message *protogen.Message, t *TableNode, virtual *protopgx.SqlVirtualField) *Field {
+	for _, field := range t.Fields {
+		if field.SqlFieldName() != virtual.GetSqlName() {
+			continue
+		}
+		if typ := virtual.GetSqlType().GetType(); field.GetTypeInfo().GetSqlType().GetType() != typ {
+			panic(fmt.Sprintf("column %s of %s must be %s", virtual.GetSqlName(), t.Name, typ))
+		}
+		return field
+	}
+	field := NewFromVirtualField(message, virtual)
+	t.Fields = append(t.Fields, field)
+	return field
+}
+
+// versionColumn finds the version field. It must be an integer field of the
+// message, so callers hold the version read with the row and send it back
+// with updates.
+func versionColumn(t *TableNode, column string) *Field {
+	for _, field := range t.Fields {
+		if field.SqlFieldName() != column {
+			continue
+		}
+		switch field.GetTypeInfo().GetSqlType().GetType() {
+		case protopgx.SqlFiledType_BIGINT, protopgx.SqlFiledType_INTEGER, protopgx.SqlFiledType_SMALLINT:
+			if !field.Virtual && !field.Embedded && field.GetFromOneOfField() == "" {
+				return field
+			}
+		}
+		break
+	}
+	panic(fmt.Sprintf("version column %s of %s must be an integer field of the message", column, t.Name))
+}
+
+func timestampColumn(message *protogen.Message, t *TableNode, column, defaultColumn string) *Field {
+	if column == "" {
+		column = defaultColumn
+	}
+	return tableColumn(message, t, &protopgx.SqlVirtualField{
+		SqlName:     column,
+		SqlType:     &protopgx.SqlType{Type: protopgx.SqlFiledType_TIMESTAMPTZ},
+		Constraints: &protopgx.SqlConstraint{DefaultValue: "NOW()"},
+	})
+}
+
 func (t *TableNode) FindField(name string) (*Field, bool) {
 	for _, field := range t.Fields {
 		if strcase.ToSnake(string(protoreflect.FullName(field.ProtoName).Name())) == name {
@@ -193,6 +287,20 @@ func CollectTablesFromProto(files []*protogen.File) []*TableNode {
 				OneOfs:          make(map[protoreflect.FullName]*Encapsulation),
 				Embeds:          make(map[protoreflect.FullName]*Encapsulation),
 			}
+			if column := sqlTable.GetSoftDelete().GetColumn(); column != "" {
+				t.SoftDelete = tableColumn(message, t, &protopgx.SqlVirtualField{
+					SqlName:    column,
+					SqlType:    &protopgx.SqlType{Type: protopgx.SqlFiledType_TIMESTAMPTZ},
+					IsNullable: true,
+				})
+			}
+			if column := sqlTable.GetVersionColumn(); column != "" {
+				t.Version = versionColumn(t, column)
+			}
+			if timestamps := sqlTable.GetTimestamps(); timestamps != nil {
+				t.CreatedAt = timestampColumn(message, t, timestamps.GetCreatedAt(), "created_at")
+				t.UpdatedAt = timestampColumn(message, t, timestamps.GetUpdatedAt(), "updated_at")
+			}
 			for _, field := range t.Fields {
 				if field.GetFromOneOfField() != "" {
 					if off, ok := t.OneOfs[protoreflect.FullName(field.GetFromOneOfField())]; ok {
@@ -299,6 +407,7 @@ func collectRelations(files []*protogen.File, tables []*TableNode) []*TableNode
 						From:      targetTable,
 						FromField: targetField,
 						ToField:   sourceField,
+						Name:      field.Desc.Name(),
 					})
 
 				case *protopgx.SqlRelation_ManyToMany_:
@@ -346,7 +455,7 @@ func collectRelations(files []*protogen.File, tables []*TableNode) []*TableNode
 						ParsedField: &protopgx.ParsedField{
 							ProtoName: fmt.Sprintf("%s_id", sourceTable.SqlTableName()),
 							Virtual:   true,
-							TypeInfo:  targetField.GetTypeInfo(),
+							TypeInfo:  sourceField.GetTypeInfo(),
 							Constraint: &protopgx.SqlConstraint{
 								Constraint: help.StringOrDefault(
 									relation.GetManyToMany().GetBackRefConstraint(),
@@ -376,8 +485,8 @@ func collectRelations(files []*protogen.File, tables []*TableNode) []*TableNode
 						Constraints: help.ListStringOrDefault(relation.GetManyToMany().GetTable().GetConstraints(), []string{
 							fmt.Sprintf(
 								"UNIQUE (%s, %s)",
-								protoreflect.FullName(sourceField.ProtoName).Name(),
-								protoreflect.FullName(targetField.ProtoName).Name(),
+								newBackwardRelField.SqlFieldName(),
+								newForwardRelField.SqlFieldName(),
 							),
 						}),
 						Virtual: true,
@@ -407,6 +516,15 @@ func collectRelations(files []*protogen.File, tables []*TableNode) []*TableNode
 						FromField: newForwardRelField,
 						ToField:   targetField,
 					})
+					sourceTable.Associations = append(sourceTable.Associations, &ManyToMany{
+						Name:        field.Desc.Name(),
+						OwnerKey:    sourceField,
+						Join:        virtualTable,
+						OwnerField:  newBackwardRelField,
+						TargetField: newForwardRelField,
+						Target:      targetTable,
+						TargetKey:   targetField,
+					})
 				}
 			}
 		}